@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Approvers record their decision about a SupportArchive in these annotations instead of the status, so that they
+// do not need write access to the whole status. The approval webhook validates the annotations and the operator copies
+// them to the approval in the status.
+const (
+	// AnnotationApprovalDecision is either Approved or Rejected.
+	AnnotationApprovalDecision = "k8s.cloudogu.com/approval-decision"
+	// AnnotationApprovalDecidedBy is the user who made the decision.
+	AnnotationApprovalDecidedBy = "k8s.cloudogu.com/approval-decided-by"
+	// AnnotationApprovalDecidedAt is the time of the decision in RFC 3339 format.
+	AnnotationApprovalDecidedAt = "k8s.cloudogu.com/approval-decided-at"
+	// AnnotationApprovalReason explains the decision.
+	AnnotationApprovalReason = "k8s.cloudogu.com/approval-reason"
+)
+
+var approvalAnnotations = []string{AnnotationApprovalDecision, AnnotationApprovalDecidedBy, AnnotationApprovalDecidedAt, AnnotationApprovalReason}
+
+// ApprovalAnnotations returns the annotations that record the approval.
+func ApprovalAnnotations(approval ApprovalStatus) map[string]string {
+	annotations := map[string]string{
+		AnnotationApprovalDecision:  string(approval.Decision),
+		AnnotationApprovalDecidedBy: approval.DecidedBy,
+		AnnotationApprovalDecidedAt: approval.DecidedAt.UTC().Format(time.RFC3339),
+	}
+	if approval.Reason != "" {
+		annotations[AnnotationApprovalReason] = approval.Reason
+	}
+
+	return annotations
+}
+
+// HasApprovalAnnotations returns true if any of the approval annotations is set.
+func HasApprovalAnnotations(annotations map[string]string) bool {
+	for _, key := range approvalAnnotations {
+		if _, ok := annotations[key]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ApprovalFromAnnotations returns the approval recorded in the annotations or nil if none of the approval annotations
+// is set. It returns an error if the annotations are incomplete or invalid.
+func ApprovalFromAnnotations(annotations map[string]string) (*ApprovalStatus, error) {
+	if !HasApprovalAnnotations(annotations) {
+		return nil, nil
+	}
+
+	decision := ApprovalDecision(annotations[AnnotationApprovalDecision])
+	if decision != ApprovalDecisionApproved && decision != ApprovalDecisionRejected {
+		return nil, fmt.Errorf("annotation %s must be %s or %s, not %q", AnnotationApprovalDecision, ApprovalDecisionApproved, ApprovalDecisionRejected, decision)
+	}
+	decidedBy := annotations[AnnotationApprovalDecidedBy]
+	if decidedBy == "" {
+		return nil, fmt.Errorf("annotation %s must not be empty", AnnotationApprovalDecidedBy)
+	}
+	decidedAt, err := time.Parse(time.RFC3339, annotations[AnnotationApprovalDecidedAt])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s: %w", AnnotationApprovalDecidedAt, err)
+	}
+
+	return &ApprovalStatus{
+		Decision:  decision,
+		DecidedBy: decidedBy,
+		DecidedAt: metav1.NewTime(decidedAt),
+		Reason:    annotations[AnnotationApprovalReason],
+	}, nil
+}
@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApprovalFromAnnotations(t *testing.T) {
+	t.Run("should read approval written by ApprovalAnnotations", func(t *testing.T) {
+		// given
+		approval := ApprovalStatus{Decision: ApprovalDecisionApproved, DecidedBy: "bob", DecidedAt: testStartTime, Reason: "ticket SUP-1"}
+		annotations := ApprovalAnnotations(approval)
+		annotations["note"] = "incident"
+
+		// when
+		actual, err := ApprovalFromAnnotations(annotations)
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, actual)
+		assert.Equal(t, approval.Decision, actual.Decision)
+		assert.Equal(t, approval.DecidedBy, actual.DecidedBy)
+		assert.True(t, approval.DecidedAt.Equal(&actual.DecidedAt))
+		assert.Equal(t, approval.Reason, actual.Reason)
+	})
+	t.Run("should return nil without approval annotations", func(t *testing.T) {
+		// when
+		actual, err := ApprovalFromAnnotations(map[string]string{"note": "incident"})
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, actual)
+	})
+	t.Run("should fail for invalid decision", func(t *testing.T) {
+		// when
+		_, err := ApprovalFromAnnotations(map[string]string{AnnotationApprovalDecision: "Maybe"})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "annotation k8s.cloudogu.com/approval-decision must be Approved or Rejected, not \"Maybe\"")
+	})
+	t.Run("should fail without decider", func(t *testing.T) {
+		// when
+		_, err := ApprovalFromAnnotations(map[string]string{AnnotationApprovalDecision: "Rejected"})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "annotation k8s.cloudogu.com/approval-decided-by must not be empty")
+	})
+	t.Run("should fail for invalid time", func(t *testing.T) {
+		// when
+		_, err := ApprovalFromAnnotations(map[string]string{
+			AnnotationApprovalDecision:  "Rejected",
+			AnnotationApprovalDecidedBy: "bob",
+			AnnotationApprovalDecidedAt: "yesterday",
+		})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse annotation k8s.cloudogu.com/approval-decided-at")
+	})
+}
@@ -0,0 +1,283 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v2 "github.com/cloudogu/k8s-support-archive-lib/api/v2"
+)
+
+// structuredErrorsAnnotation keeps the structured v2 errors on v1 objects so that converting back to v2 is lossless.
+const structuredErrorsAnnotation = "k8s.cloudogu.com/v2-errors"
+
+var _ conversion.Convertible = &SupportArchive{}
+
+// ConvertTo converts this SupportArchive to the hub version v2.
+func (src *SupportArchive) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v2.SupportArchive)
+	if !ok {
+		return fmt.Errorf("unsupported conversion target %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	excluded := src.Spec.ExcludedContents
+	dst.Spec = v2.SupportArchiveSpec{
+		IncludedContents: v2.IncludedContents{
+			SystemState:   !excluded.SystemState,
+			SensitiveData: !excluded.SensitiveData,
+			Events:        !excluded.Events,
+			Logs:          !excluded.Logs,
+			VolumeInfo:    !excluded.VolumeInfo,
+			SystemInfo:    !excluded.SystemInfo,
+		},
+		ContentTimeframe: v2.ContentTimeframe{
+			StartTime: *src.Spec.ContentTimeframe.StartTime.DeepCopy(),
+			EndTime:   *src.Spec.ContentTimeframe.EndTime.DeepCopy(),
+		},
+		Notifications:    notificationsToV2(src.Spec.Notifications),
+		Requester:        src.Spec.Requester,
+		RequiresApproval: src.Spec.RequiresApproval,
+		Ticket:           src.Spec.Ticket,
+		Reason:           src.Spec.Reason,
+		Cancel:           src.Spec.Cancel,
+	}
+
+	errs, err := restoreStructuredErrors(src)
+	if err != nil {
+		return err
+	}
+	delete(dst.Annotations, structuredErrorsAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+
+	dst.Status = v2.SupportArchiveStatus{
+		Errors:       errs,
+		DownloadPath: src.Status.DownloadPath,
+		Size:         src.Status.Size,
+		Digest:       src.Status.Digest,
+		Phase:        v2.StatusPhase(src.Status.Phase),
+		Conditions:   src.Status.DeepCopy().Conditions,
+	}
+	if src.Status.Signature != nil {
+		dst.Status.Signature = &v2.ArchiveSignature{
+			Algorithm: v2.SignatureAlgorithm(src.Status.Signature.Algorithm),
+			Value:     src.Status.Signature.Value,
+		}
+		if src.Status.Signature.KeySecretRef != nil {
+			dst.Status.Signature.KeySecretRef = &v2.SecretKeyReference{
+				Name: src.Status.Signature.KeySecretRef.Name,
+				Key:  src.Status.Signature.KeySecretRef.Key,
+			}
+		}
+	}
+	for _, redaction := range src.Status.Redactions {
+		dst.Status.Redactions = append(dst.Status.Redactions, v2.RedactionSummary{
+			Category: v2.ContentCategory(redaction.Category),
+			Count:    redaction.Count,
+		})
+	}
+	if src.Status.Approval != nil {
+		dst.Status.Approval = &v2.ApprovalStatus{
+			Decision:  v2.ApprovalDecision(src.Status.Approval.Decision),
+			DecidedBy: src.Status.Approval.DecidedBy,
+			DecidedAt: *src.Status.Approval.DecidedAt.DeepCopy(),
+			Reason:    src.Status.Approval.Reason,
+		}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts the hub version v2 to this SupportArchive.
+func (dst *SupportArchive) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v2.SupportArchive)
+	if !ok {
+		return fmt.Errorf("unsupported conversion source %T", srcRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	included := src.Spec.IncludedContents
+	dst.Spec = SupportArchiveSpec{
+		ExcludedContents: ExcludedContents{
+			SystemState:   !included.SystemState,
+			SensitiveData: !included.SensitiveData,
+			Events:        !included.Events,
+			Logs:          !included.Logs,
+			VolumeInfo:    !included.VolumeInfo,
+			SystemInfo:    !included.SystemInfo,
+		},
+		ContentTimeframe: ContentTimeframe{
+			StartTime: *src.Spec.ContentTimeframe.StartTime.DeepCopy(),
+			EndTime:   *src.Spec.ContentTimeframe.EndTime.DeepCopy(),
+		},
+		Notifications:    notificationsFromV2(src.Spec.Notifications),
+		Requester:        src.Spec.Requester,
+		RequiresApproval: src.Spec.RequiresApproval,
+		Ticket:           src.Spec.Ticket,
+		Reason:           src.Spec.Reason,
+		Cancel:           src.Spec.Cancel,
+	}
+
+	err := preserveStructuredErrors(dst, src.Status.Errors)
+	if err != nil {
+		return err
+	}
+
+	dst.Status = SupportArchiveStatus{
+		DownloadPath: src.Status.DownloadPath,
+		Size:         src.Status.Size,
+		Digest:       src.Status.Digest,
+		Phase:        StatusPhase(src.Status.Phase),
+		Conditions:   src.Status.DeepCopy().Conditions,
+	}
+	for _, archiveErr := range src.Status.Errors {
+		dst.Status.Errors = append(dst.Status.Errors, archiveErr.Message)
+	}
+	if src.Status.Signature != nil {
+		dst.Status.Signature = &ArchiveSignature{
+			Algorithm: SignatureAlgorithm(src.Status.Signature.Algorithm),
+			Value:     src.Status.Signature.Value,
+		}
+		if src.Status.Signature.KeySecretRef != nil {
+			dst.Status.Signature.KeySecretRef = &SecretKeyReference{
+				Name: src.Status.Signature.KeySecretRef.Name,
+				Key:  src.Status.Signature.KeySecretRef.Key,
+			}
+		}
+	}
+	for _, redaction := range src.Status.Redactions {
+		dst.Status.Redactions = append(dst.Status.Redactions, RedactionSummary{
+			Category: ContentCategory(redaction.Category),
+			Count:    redaction.Count,
+		})
+	}
+	if src.Status.Approval != nil {
+		dst.Status.Approval = &ApprovalStatus{
+			Decision:  ApprovalDecision(src.Status.Approval.Decision),
+			DecidedBy: src.Status.Approval.DecidedBy,
+			DecidedAt: *src.Status.Approval.DecidedAt.DeepCopy(),
+			Reason:    src.Status.Approval.Reason,
+		}
+	}
+
+	return nil
+}
+
+func notificationsToV2(src *Notifications) *v2.Notifications {
+	if src == nil {
+		return nil
+	}
+
+	dst := &v2.Notifications{}
+	for _, webhook := range src.Webhooks {
+		converted := v2.WebhookNotification{
+			Name:            webhook.Name,
+			URL:             webhook.URL,
+			Format:          v2.WebhookFormat(webhook.Format),
+			PayloadTemplate: webhook.PayloadTemplate,
+		}
+		for _, event := range webhook.Events {
+			converted.Events = append(converted.Events, v2.NotificationEvent(event))
+		}
+		for _, header := range webhook.Headers {
+			converted.Headers = append(converted.Headers, v2.WebhookHeader{
+				Name:         header.Name,
+				SecretKeyRef: v2.SecretKeyReference{Name: header.SecretKeyRef.Name, Key: header.SecretKeyRef.Key},
+			})
+		}
+		dst.Webhooks = append(dst.Webhooks, converted)
+	}
+
+	return dst
+}
+
+func notificationsFromV2(src *v2.Notifications) *Notifications {
+	if src == nil {
+		return nil
+	}
+
+	dst := &Notifications{}
+	for _, webhook := range src.Webhooks {
+		converted := WebhookNotification{
+			Name:            webhook.Name,
+			URL:             webhook.URL,
+			Format:          WebhookFormat(webhook.Format),
+			PayloadTemplate: webhook.PayloadTemplate,
+		}
+		for _, event := range webhook.Events {
+			converted.Events = append(converted.Events, NotificationEvent(event))
+		}
+		for _, header := range webhook.Headers {
+			converted.Headers = append(converted.Headers, WebhookHeader{
+				Name:         header.Name,
+				SecretKeyRef: SecretKeyReference{Name: header.SecretKeyRef.Name, Key: header.SecretKeyRef.Key},
+			})
+		}
+		dst.Webhooks = append(dst.Webhooks, converted)
+	}
+
+	return dst
+}
+
+// preserveStructuredErrors stores the given errors as annotation if they contain more than their messages.
+func preserveStructuredErrors(dst *SupportArchive, errs []v2.ArchiveError) error {
+	delete(dst.Annotations, structuredErrorsAnnotation)
+
+	structured := false
+	for _, archiveErr := range errs {
+		if archiveErr.Category != "" || archiveErr.Reason != "" {
+			structured = true
+			break
+		}
+	}
+	if !structured {
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+		return nil
+	}
+
+	value, err := json.Marshal(errs)
+	if err != nil {
+		return fmt.Errorf("failed to preserve structured errors: %w", err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[structuredErrorsAnnotation] = string(value)
+
+	return nil
+}
+
+// restoreStructuredErrors returns the preserved structured errors if they still match the error messages of src.
+// Otherwise, the errors are created from the messages alone.
+func restoreStructuredErrors(src *SupportArchive) ([]v2.ArchiveError, error) {
+	var errs []v2.ArchiveError
+	for _, message := range src.Status.Errors {
+		errs = append(errs, v2.ArchiveError{Message: message})
+	}
+
+	value, ok := src.Annotations[structuredErrorsAnnotation]
+	if !ok {
+		return errs, nil
+	}
+
+	var preserved []v2.ArchiveError
+	err := json.Unmarshal([]byte(value), &preserved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore structured errors from annotation %s: %w", structuredErrorsAnnotation, err)
+	}
+	if len(preserved) != len(errs) {
+		return errs, nil
+	}
+	for i := range preserved {
+		if preserved[i].Message != errs[i].Message {
+			return errs, nil
+		}
+	}
+
+	return preserved, nil
+}
@@ -0,0 +1,225 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/cloudogu/k8s-support-archive-lib/api/v2"
+)
+
+var (
+	testStartTime = metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	testEndTime   = metav1.NewTime(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+)
+
+func fullV1SupportArchive() *SupportArchive {
+	return &SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-archive",
+			Namespace:   "ecosystem",
+			Labels:      map[string]string{"app": "ces"},
+			Annotations: map[string]string{"note": "incident"},
+		},
+		Spec: SupportArchiveSpec{
+			ExcludedContents: ExcludedContents{SensitiveData: true, Logs: true},
+			ContentTimeframe: ContentTimeframe{StartTime: testStartTime, EndTime: testEndTime},
+			Notifications: &Notifications{Webhooks: []WebhookNotification{{
+				Name:            "on-call",
+				URL:             "https://hooks.example.com/sar",
+				Events:          []NotificationEvent{NotificationEventCreated, NotificationEventCollectorFailed},
+				Format:          WebhookFormatSlack,
+				PayloadTemplate: `{"text": {{ json .Message }}}`,
+				Headers:         []WebhookHeader{{Name: "Authorization", SecretKeyRef: SecretKeyReference{Name: "webhook", Key: "token"}}},
+			}}},
+			Requester:        "alice",
+			RequiresApproval: true,
+			Ticket:           "SUP-1234",
+			Reason:           "operator crashes after upgrade",
+			Cancel:           true,
+		},
+		Status: SupportArchiveStatus{
+			Errors:       []string{"failed to collect logs", "failed to collect events"},
+			DownloadPath: "/archives/my-archive.zip",
+			Size:         42,
+			Digest:       "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			Signature: &ArchiveSignature{
+				Algorithm:    SignatureAlgorithmEd25519,
+				Value:        "c2lnbmF0dXJl",
+				KeySecretRef: &SecretKeyReference{Name: "signing-key", Key: "key.pem"},
+			},
+			Phase:      StatusPhaseCreated,
+			Conditions: []metav1.Condition{{Type: ConditionSupportArchiveCreated, Status: metav1.ConditionTrue, Reason: "AllCollectorsExecuted", LastTransitionTime: testEndTime}},
+			Redactions: []RedactionSummary{{Category: ContentSensitiveData, Count: 3}},
+			Approval:   &ApprovalStatus{Decision: ApprovalDecisionApproved, DecidedBy: "bob", DecidedAt: testEndTime, Reason: "ticket checked"},
+		},
+	}
+}
+
+func fullV2SupportArchive() *v2.SupportArchive {
+	return &v2.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-archive",
+			Namespace:   "ecosystem",
+			Labels:      map[string]string{"app": "ces"},
+			Annotations: map[string]string{"note": "incident"},
+		},
+		Spec: v2.SupportArchiveSpec{
+			IncludedContents: v2.IncludedContents{SystemState: true, Events: true, VolumeInfo: true, SystemInfo: true},
+			ContentTimeframe: v2.ContentTimeframe{StartTime: testStartTime, EndTime: testEndTime},
+			Notifications: &v2.Notifications{Webhooks: []v2.WebhookNotification{{
+				Name:            "on-call",
+				URL:             "https://hooks.example.com/sar",
+				Events:          []v2.NotificationEvent{v2.NotificationEventCreated, v2.NotificationEventCollectorFailed},
+				Format:          v2.WebhookFormatSlack,
+				PayloadTemplate: `{"text": {{ json .Message }}}`,
+				Headers:         []v2.WebhookHeader{{Name: "Authorization", SecretKeyRef: v2.SecretKeyReference{Name: "webhook", Key: "token"}}},
+			}}},
+			Requester:        "alice",
+			RequiresApproval: true,
+			Ticket:           "SUP-1234",
+			Reason:           "operator crashes after upgrade",
+			Cancel:           true,
+		},
+		Status: v2.SupportArchiveStatus{
+			Errors: []v2.ArchiveError{
+				{Category: v2.ContentLogs, Reason: "LogsUnavailable", Message: "failed to collect logs"},
+				{Message: "failed to collect events"},
+			},
+			DownloadPath: "/archives/my-archive.zip",
+			Size:         42,
+			Digest:       "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			Signature: &v2.ArchiveSignature{
+				Algorithm:    v2.SignatureAlgorithmEd25519,
+				Value:        "c2lnbmF0dXJl",
+				KeySecretRef: &v2.SecretKeyReference{Name: "signing-key", Key: "key.pem"},
+			},
+			Phase:      v2.StatusPhaseCreated,
+			Conditions: []metav1.Condition{{Type: v2.ConditionSupportArchiveCreated, Status: metav1.ConditionTrue, Reason: "AllCollectorsExecuted", LastTransitionTime: testEndTime}},
+			Redactions: []v2.RedactionSummary{{Category: v2.ContentSensitiveData, Count: 3}},
+			Approval:   &v2.ApprovalStatus{Decision: v2.ApprovalDecisionApproved, DecidedBy: "bob", DecidedAt: testEndTime, Reason: "ticket checked"},
+		},
+	}
+}
+
+func TestSupportArchive_ConvertTo(t *testing.T) {
+	t.Run("should invert excluded contents", func(t *testing.T) {
+		// given
+		src := fullV1SupportArchive()
+		dst := &v2.SupportArchive{}
+
+		// when
+		err := src.ConvertTo(dst)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, v2.IncludedContents{SystemState: true, Events: true, VolumeInfo: true, SystemInfo: true}, dst.Spec.IncludedContents)
+		assert.Equal(t, []v2.ArchiveError{{Message: "failed to collect logs"}, {Message: "failed to collect events"}}, dst.Status.Errors)
+		assert.Equal(t, src.Spec.ContentTimeframe.StartTime, dst.Spec.ContentTimeframe.StartTime)
+	})
+	t.Run("should ignore preserved errors that do not match the messages anymore", func(t *testing.T) {
+		// given
+		src := &SupportArchive{}
+		require.NoError(t, src.ConvertFrom(fullV2SupportArchive()))
+		src.Status.Errors = append(src.Status.Errors, "another error")
+		dst := &v2.SupportArchive{}
+
+		// when
+		err := src.ConvertTo(dst)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, dst.Status.Errors, 3)
+		assert.Equal(t, v2.ArchiveError{Message: "failed to collect logs"}, dst.Status.Errors[0])
+		assert.NotContains(t, dst.Annotations, structuredErrorsAnnotation)
+	})
+	t.Run("should fail for invalid preserved errors", func(t *testing.T) {
+		// given
+		src := fullV1SupportArchive()
+		src.Annotations[structuredErrorsAnnotation] = "{"
+
+		// when
+		err := src.ConvertTo(&v2.SupportArchive{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to restore structured errors")
+	})
+}
+
+func TestSupportArchive_ConvertFrom(t *testing.T) {
+	t.Run("should preserve structured errors as annotation", func(t *testing.T) {
+		// given
+		dst := &SupportArchive{}
+
+		// when
+		err := dst.ConvertFrom(fullV2SupportArchive())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, ExcludedContents{SensitiveData: true, Logs: true}, dst.Spec.ExcludedContents)
+		assert.Equal(t, []string{"failed to collect logs", "failed to collect events"}, dst.Status.Errors)
+		assert.Contains(t, dst.Annotations, structuredErrorsAnnotation)
+		assert.Equal(t, "incident", dst.Annotations["note"])
+	})
+	t.Run("should not add annotation for errors with messages only", func(t *testing.T) {
+		// given
+		src := fullV2SupportArchive()
+		src.Annotations = nil
+		src.Status.Errors = []v2.ArchiveError{{Message: "failed"}}
+		dst := &SupportArchive{}
+
+		// when
+		err := dst.ConvertFrom(src)
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, dst.Annotations)
+	})
+}
+
+func TestSupportArchive_RoundTrip(t *testing.T) {
+	t.Run("v1 to v2 to v1", func(t *testing.T) {
+		// given
+		original := fullV1SupportArchive()
+		hub := &v2.SupportArchive{}
+		result := &SupportArchive{}
+
+		// when
+		require.NoError(t, original.DeepCopy().ConvertTo(hub))
+		require.NoError(t, result.ConvertFrom(hub))
+
+		// then
+		assert.Equal(t, original, result)
+	})
+	t.Run("v1 to v2 to v1 with empty object", func(t *testing.T) {
+		// given
+		original := &SupportArchive{}
+		hub := &v2.SupportArchive{}
+		result := &SupportArchive{}
+
+		// when
+		require.NoError(t, original.DeepCopy().ConvertTo(hub))
+		require.NoError(t, result.ConvertFrom(hub))
+
+		// then
+		assert.Equal(t, original, result)
+	})
+	t.Run("v2 to v1 to v2", func(t *testing.T) {
+		// given
+		original := fullV2SupportArchive()
+		spoke := &SupportArchive{}
+		result := &v2.SupportArchive{}
+
+		// when
+		require.NoError(t, spoke.ConvertFrom(original.DeepCopy()))
+		require.NoError(t, spoke.ConvertTo(result))
+
+		// then
+		assert.Equal(t, original, result)
+	})
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SupportArchivePolicySpec defines the rules for SupportArchives in all namespaces of the cluster.
+type SupportArchivePolicySpec struct {
+	// Defaults are applied to new SupportArchives that do not set the fields themselves.
+	// +optional
+	Defaults *PolicyDefaults `json:"defaults,omitempty"`
+	// Limits restrict the SupportArchives that may be created.
+	// +optional
+	Limits *PolicyLimits `json:"limits,omitempty"`
+	// ForbiddenContents are content categories that every SupportArchive has to exclude, e.g. `SensitiveData`.
+	// The categories are SystemState, SensitiveData, Events, Logs, VolumeInfo and SystemInfo.
+	// +optional
+	// +listType=set
+	// +kubebuilder:validation:MaxItems=6
+	ForbiddenContents []ContentCategory `json:"forbiddenContents,omitempty"`
+}
+
+type PolicyDefaults struct {
+	// Timeframe is the length of the content timeframe ending at the creation of a SupportArchive without timeframe.
+	// +optional
+	Timeframe *metav1.Duration `json:"timeframe,omitempty"`
+	// RequiresApproval lets new SupportArchives wait for the approval of another user than their requester.
+	// +optional
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
+	// Notifications are used for SupportArchives without notifications.
+	// +optional
+	Notifications *Notifications `json:"notifications,omitempty"`
+}
+
+type PolicyLimits struct {
+	// MaxTimeframe is the maximal length of the content timeframe of a SupportArchive.
+	// +optional
+	MaxTimeframe *metav1.Duration `json:"maxTimeframe,omitempty"`
+	// MaxConcurrentPerNamespace is the maximal number of SupportArchives per namespace that are not created yet.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentPerNamespace *int32 `json:"maxConcurrentPerNamespace,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName="sarpolicy"
+// +kubebuilder:metadata:labels=app=ces;app.kubernetes.io/name=k8s-support-archive-operator;k8s.cloudogu.com/component.name=k8s-support-archive-operator-crd
+// +kubebuilder:printcolumn:name="Max Timeframe",type="string",JSONPath=".spec.limits.maxTimeframe",description="The maximal length of the content timeframe"
+// +kubebuilder:printcolumn:name="Max Concurrent",type="integer",JSONPath=".spec.limits.maxConcurrentPerNamespace",description="The maximal number of SupportArchives per namespace that are not created yet"
+// +kubebuilder:printcolumn:name="Forbidden",type="string",JSONPath=".spec.forbiddenContents",description="The content categories every SupportArchive has to exclude"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of the resource"
+
+// SupportArchivePolicy is the Schema for the supportarchivepolicies API. It enforces defaults and limits for the
+// SupportArchives in all namespaces. If several policies exist, all limits apply and the defaults of the policy
+// with the alphabetically first name win.
+type SupportArchivePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	Spec SupportArchivePolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// SupportArchivePolicyList contains a list of SupportArchivePolicy.
+type SupportArchivePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SupportArchivePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SupportArchivePolicy{}, &SupportArchivePolicyList{})
+}
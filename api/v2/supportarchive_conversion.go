@@ -0,0 +1,8 @@
+package v2
+
+import "sigs.k8s.io/controller-runtime/pkg/conversion"
+
+var _ conversion.Hub = &SupportArchive{}
+
+// Hub marks this type as a conversion hub. All other versions of SupportArchive convert from and to v2.
+func (*SupportArchive) Hub() {}
@@ -0,0 +1,360 @@
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ConditionSupportArchiveCreated = "Created"
+	ConditionVolumeInfoFetched     = "VolumeInfoFetched"
+	ConditionNodeInfoFetched       = "NodeInfoFetched"
+	ConditionSecretsFetched        = "SecretsFetched"
+	// ConditionCancelled is set by the operator when it stopped the creation of a SupportArchive because of Cancel.
+	ConditionCancelled = "Cancelled"
+)
+
+// StatusPhase summarizes the progress of a SupportArchive.
+type StatusPhase string
+
+const (
+	// StatusPhaseInProgress means that the contents of the SupportArchive are collected.
+	StatusPhaseInProgress StatusPhase = "InProgress"
+	// StatusPhaseCreated means that the SupportArchive is ready for download.
+	StatusPhaseCreated StatusPhase = "Created"
+	// StatusPhaseCancelled means that the creation of the SupportArchive was stopped because of Cancel.
+	StatusPhaseCancelled StatusPhase = "Cancelled"
+)
+
+// ContentCategory names one of the categories of content that can be contained in a SupportArchive.
+// +kubebuilder:validation:Enum=SystemState;SensitiveData;Events;Logs;VolumeInfo;SystemInfo
+type ContentCategory string
+
+const (
+	ContentSystemState   ContentCategory = "SystemState"
+	ContentSensitiveData ContentCategory = "SensitiveData"
+	ContentEvents        ContentCategory = "Events"
+	ContentLogs          ContentCategory = "Logs"
+	ContentVolumeInfo    ContentCategory = "VolumeInfo"
+	ContentSystemInfo    ContentCategory = "SystemInfo"
+)
+
+// SupportArchiveSpec defines the desired state of SupportArchive.
+// +kubebuilder:validation:XValidation:rule="has(self.requester) == has(oldSelf.requester)",message="Requester is immutable"
+// +kubebuilder:validation:XValidation:rule="(has(self.requiresApproval) && self.requiresApproval) == (has(oldSelf.requiresApproval) && oldSelf.requiresApproval)",message="RequiresApproval is immutable"
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.cancel) || !oldSelf.cancel || (has(self.cancel) && self.cancel)",message="Cancel cannot be reverted"
+// +kubebuilder:validation:XValidation:rule="has(self.ticket) == has(oldSelf.ticket)",message="Ticket is immutable"
+// +kubebuilder:validation:XValidation:rule="has(self.reason) == has(oldSelf.reason)",message="Reason is immutable"
+// +kubebuilder:validation:XValidation:rule="has(self.notifications) == has(oldSelf.notifications)",message="Notifications are immutable"
+type SupportArchiveSpec struct {
+	// IncludedContents selects which contents are included in the SupportArchive.
+	// Contents that are not selected are not collected.
+	// +required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="IncludedContents is immutable"
+	IncludedContents IncludedContents `json:"includedContents"`
+	// ContentTimeframe defines the timeframe of the contents in the supportArchive.
+	// +required
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="ContentTimeframe is immutable"
+	ContentTimeframe ContentTimeframe `json:"contentTimeframe"`
+	// Notifications configures who is notified about the progress of the SupportArchive.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Notifications are immutable"
+	Notifications *Notifications `json:"notifications,omitempty"`
+	// Requester is the user who requested the SupportArchive.
+	// If the requester webhook is enabled, it is set to the authenticated user that creates the SupportArchive.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Requester is immutable"
+	Requester string `json:"requester,omitempty"`
+	// RequiresApproval defines that the SupportArchive must be approved by another user than the Requester before
+	// its contents are collected. Approvers record the decision in the annotations k8s.cloudogu.com/approval-*,
+	// which the operator copies to the status.
+	// +optional
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
+	// Ticket references the support case the SupportArchive was created for, e.g. `SUP-1234`.
+	// +optional
+	// +kubebuilder:validation:MaxLength=128
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Ticket is immutable"
+	Ticket string `json:"ticket,omitempty"`
+	// Reason describes why the SupportArchive was created.
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Reason is immutable"
+	Reason string `json:"reason,omitempty"`
+	// Cancel stops the creation of the SupportArchive. Unlike all other fields of the spec, it can be set after the
+	// creation, but it cannot be reverted. The operator acknowledges the cancellation with the Cancelled condition.
+	// +optional
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+type IncludedContents struct {
+	// SystemState concerns all Kubernetes resources (excluding Secrets) with label `app: ces`.
+	// +optional
+	SystemState bool `json:"systemState,omitempty"`
+	// SensitiveData concerns Secrets with label `app: ces`.
+	// They will be censored even if included.
+	// +optional
+	SensitiveData bool `json:"sensitiveData,omitempty"`
+	// Events concerns Kubernetes events.
+	// +optional
+	Events bool `json:"events,omitempty"`
+	// Logs concerns application logs.
+	// +optional
+	Logs bool `json:"logs,omitempty"`
+	// VolumeInfo concerns metrics about volumes.
+	// +optional
+	VolumeInfo bool `json:"volumeInfo,omitempty"`
+	// SystemInfo concerns information about the system like the kubernetes version and nodes.
+	// +optional
+	SystemInfo bool `json:"systemInfo,omitempty"`
+}
+
+type ContentTimeframe struct {
+	// StartTime is the minimal time from when logs and events should be included.
+	// +required
+	StartTime metav1.Time `json:"startTime"`
+	// EndTime is the maximal time from when logs and events should be included.
+	// +required
+	EndTime metav1.Time `json:"endTime"`
+}
+
+// NotificationEvent names a step in the lifecycle of a SupportArchive that notifications can be sent for.
+// The values match the reasons of the Kubernetes events recorded for these steps.
+// +kubebuilder:validation:Enum=CollectionStarted;CollectorFailed;Created
+type NotificationEvent string
+
+const (
+	NotificationEventCollectionStarted NotificationEvent = "CollectionStarted"
+	NotificationEventCollectorFailed   NotificationEvent = "CollectorFailed"
+	NotificationEventCreated           NotificationEvent = "Created"
+)
+
+// WebhookFormat names the payload format of a webhook.
+type WebhookFormat string
+
+const (
+	// WebhookFormatGeneric sends a JSON object with the event, the SupportArchive and a message.
+	WebhookFormatGeneric WebhookFormat = "Generic"
+	// WebhookFormatSlack sends a message that Slack incoming webhooks accept.
+	WebhookFormatSlack WebhookFormat = "Slack"
+	// WebhookFormatTeams sends a message that Microsoft Teams incoming webhooks accept.
+	WebhookFormatTeams WebhookFormat = "Teams"
+)
+
+// Notifications configures the notifications sent for a SupportArchive.
+type Notifications struct {
+	// Webhooks receive an HTTP POST request for every configured event.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Webhooks []WebhookNotification `json:"webhooks,omitempty"`
+}
+
+// WebhookNotification sends notifications to a generic HTTP webhook.
+type WebhookNotification struct {
+	// Name identifies the webhook within the SupportArchive.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+	// URL is the HTTP or HTTPS endpoint of the webhook.
+	// +required
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// +kubebuilder:validation:MaxLength=2048
+	URL string `json:"url"`
+	// Events are the lifecycle steps the webhook is notified about.
+	// +required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=3
+	// +listType=set
+	Events []NotificationEvent `json:"events"`
+	// Format selects the payload format. It is ignored if a PayloadTemplate is set.
+	// +optional
+	// +kubebuilder:validation:Enum=Generic;Slack;Teams
+	// +kubebuilder:default=Generic
+	Format WebhookFormat `json:"format,omitempty"`
+	// PayloadTemplate is a Go template rendering the JSON payload. It can use the fields
+	// `.Event`, `.Name`, `.Namespace`, `.Message` and `.DownloadPath`. The function `json` quotes a value as JSON string,
+	// e.g. `{"text": {{ json .Message }}}`.
+	// +optional
+	// +kubebuilder:validation:MaxLength=4096
+	PayloadTemplate string `json:"payloadTemplate,omitempty"`
+	// Headers are added to every request, e.g. to authenticate at the webhook.
+	// Their values are read from Secrets so that credentials are not part of the SupportArchive. Only Secrets with the
+	// label `k8s.cloudogu.com/support-archive-notification: "true"` can be referenced.
+	// +listType=map
+	// +listMapKey=name
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Headers []WebhookHeader `json:"headers,omitempty"`
+}
+
+// WebhookHeader is an HTTP header whose value is read from a Secret.
+type WebhookHeader struct {
+	// Name is the name of the HTTP header.
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=256
+	Name string `json:"name"`
+	// SecretKeyRef references the Secret key holding the header value.
+	// +required
+	SecretKeyRef SecretKeyReference `json:"secretKeyRef"`
+}
+
+// SupportArchiveStatus defines the observed state of SupportArchive.
+// +kubebuilder:validation:XValidation:rule="!has(oldSelf.approval) || has(self.approval)",message="Approval cannot be removed"
+type SupportArchiveStatus struct {
+	// Errors contains the errors that accumulated during execution.
+	// +optional
+	Errors []ArchiveError `json:"errors,omitempty"`
+	// DownloadPath exposes where the created archive can be obtained.
+	// +optional
+	DownloadPath string `json:"downloadPath,omitempty"`
+	// Size is the size of the created archive in bytes.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+	// Digest is the SHA-256 digest of the created archive in the form `sha256:<hex>`.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
+	// Signature is the detached signature of the created archive.
+	// It is only set if the operator is configured with a signing key.
+	// +optional
+	Signature *ArchiveSignature `json:"signature,omitempty"`
+	// Phase summarizes the progress of the support archive creation.
+	// +optional
+	// +kubebuilder:validation:Enum=InProgress;Created;Cancelled
+	Phase StatusPhase `json:"phase,omitempty"`
+	// Conditions exposes the actual progress of the support archive creation.
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Redactions summarizes how many values were censored per content category.
+	// The details of every redaction are recorded in the redaction report inside the archive.
+	// +listType=map
+	// +listMapKey=category
+	// +optional
+	Redactions []RedactionSummary `json:"redactions,omitempty"`
+	// Approval is the decision about a SupportArchive that requires approval. It cannot be changed or removed once
+	// made. The operator sets it from the approval annotations validated by the approval webhook.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Approval is immutable"
+	Approval *ApprovalStatus `json:"approval,omitempty"`
+}
+
+// ApprovalDecision is the decision about a SupportArchive that requires approval.
+type ApprovalDecision string
+
+const (
+	ApprovalDecisionApproved ApprovalDecision = "Approved"
+	ApprovalDecisionRejected ApprovalDecision = "Rejected"
+)
+
+// ApprovalStatus records who approved or rejected a SupportArchive and when.
+type ApprovalStatus struct {
+	// Decision is either Approved or Rejected.
+	// +required
+	// +kubebuilder:validation:Enum=Approved;Rejected
+	Decision ApprovalDecision `json:"decision"`
+	// DecidedBy is the user who made the decision.
+	// +required
+	DecidedBy string `json:"decidedBy"`
+	// DecidedAt is the time of the decision.
+	// +required
+	DecidedAt metav1.Time `json:"decidedAt"`
+	// Reason explains the decision.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// ArchiveError describes an error that occurred during the creation of the archive.
+type ArchiveError struct {
+	// Category is the content category whose collection failed, if the error concerns a single category.
+	// +optional
+	Category ContentCategory `json:"category,omitempty"`
+	// Reason is a programmatic identifier in CamelCase describing the cause of the error.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable description of the error.
+	// +required
+	Message string `json:"message"`
+}
+
+// SignatureAlgorithm names the algorithm of an archive signature.
+type SignatureAlgorithm string
+
+const (
+	SignatureAlgorithmEd25519 SignatureAlgorithm = "ed25519"
+)
+
+// ArchiveSignature is a detached signature over the SHA-256 digest of an archive.
+type ArchiveSignature struct {
+	// Algorithm is the algorithm used to create the signature.
+	// +required
+	// +kubebuilder:validation:Enum=ed25519
+	Algorithm SignatureAlgorithm `json:"algorithm"`
+	// Value is the base64 encoded signature.
+	// +required
+	Value string `json:"value"`
+	// KeySecretRef references the Secret holding the key pair that was used for signing.
+	// Its public key can be used to verify the signature.
+	// +optional
+	KeySecretRef *SecretKeyReference `json:"keySecretRef,omitempty"`
+}
+
+// SecretKeyReference references a key of a Secret in the namespace of the SupportArchive.
+type SecretKeyReference struct {
+	// Name is the name of the Secret.
+	// +required
+	// +kubebuilder:validation:MaxLength=253
+	Name string `json:"name"`
+	// Key is the key inside the Secret.
+	// +required
+	// +kubebuilder:validation:MaxLength=253
+	Key string `json:"key"`
+}
+
+// RedactionSummary contains the number of censored values of a content category.
+type RedactionSummary struct {
+	// Category is the content category the censored values belong to.
+	// +required
+	Category ContentCategory `json:"category"`
+	// Count is the number of values censored in this category.
+	// +required
+	Count int64 `json:"count"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:metadata:labels=app=ces;app.kubernetes.io/name=k8s-support-archive-operator;k8s.cloudogu.com/component.name=k8s-support-archive-operator-crd
+// +kubebuilder:resource:shortName="sar"
+// +kubebuilder:printcolumn:name="Requester",type="string",JSONPath=".spec.requester",description="The user who requested the SupportArchive"
+// +kubebuilder:printcolumn:name="Ticket",type="string",JSONPath=".spec.ticket",description="The support case the SupportArchive was created for"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The progress of the SupportArchive"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="The age of the resource"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.reason",description="Why the SupportArchive was created",priority=1
+
+// SupportArchive is the Schema for the supportarchives API.
+type SupportArchive struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +required
+	Spec   SupportArchiveSpec   `json:"spec"`
+	Status SupportArchiveStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SupportArchiveList contains a list of SupportArchive.
+type SupportArchiveList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SupportArchive `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SupportArchive{}, &SupportArchiveList{})
+}
@@ -0,0 +1,278 @@
+// Package builder constructs SupportArchive objects and validates them before they are sent to the API server.
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// DefaultTimeframe is the length of the content timeframe if neither Timeframe nor Last is used.
+const DefaultTimeframe = 24 * time.Hour
+
+// maxTicketLength and maxReasonLength match the validation of the spec fields in the CRD.
+const (
+	maxTicketLength = 128
+	maxReasonLength = 1024
+)
+
+// allCategories contains every content category of a SupportArchive.
+var allCategories = []v1.ContentCategory{
+	v1.ContentSystemState,
+	v1.ContentSensitiveData,
+	v1.ContentEvents,
+	v1.ContentLogs,
+	v1.ContentVolumeInfo,
+	v1.ContentSystemInfo,
+}
+
+// SupportArchiveBuilder builds a SupportArchive. All contents are included and the timeframe covers the
+// DefaultTimeframe until now, unless configured otherwise. Invalid input is reported by Build.
+type SupportArchiveBuilder struct {
+	name        string
+	namespace   string
+	labels      map[string]string
+	annotations map[string]string
+	excluded    map[v1.ContentCategory]bool
+	startTime   time.Time
+	endTime     time.Time
+	duration    time.Duration
+	requester   string
+	approval    bool
+	ticket      string
+	reason      string
+	now         func() time.Time
+	errs        []error
+}
+
+// NewSupportArchive starts building a SupportArchive with the given name and namespace. The namespace may be empty
+// if the client sets it.
+func NewSupportArchive(name string, namespace string) *SupportArchiveBuilder {
+	return &SupportArchiveBuilder{
+		name:      name,
+		namespace: namespace,
+		excluded:  map[v1.ContentCategory]bool{},
+		duration:  DefaultTimeframe,
+		now:       time.Now,
+	}
+}
+
+// IncludeOnly includes the given content categories and excludes all others.
+func (b *SupportArchiveBuilder) IncludeOnly(categories ...v1.ContentCategory) *SupportArchiveBuilder {
+	for _, category := range allCategories {
+		b.excluded[category] = true
+	}
+	for _, category := range categories {
+		b.setExcluded(category, false)
+	}
+
+	return b
+}
+
+// Exclude excludes the given content categories.
+func (b *SupportArchiveBuilder) Exclude(categories ...v1.ContentCategory) *SupportArchiveBuilder {
+	for _, category := range categories {
+		b.setExcluded(category, true)
+	}
+
+	return b
+}
+
+// Timeframe sets the start and end time of the contents.
+func (b *SupportArchiveBuilder) Timeframe(startTime time.Time, endTime time.Time) *SupportArchiveBuilder {
+	return b.StartingAt(startTime).EndingAt(endTime)
+}
+
+// StartingAt sets the start time of the contents. It replaces a duration set with Last.
+func (b *SupportArchiveBuilder) StartingAt(startTime time.Time) *SupportArchiveBuilder {
+	b.startTime = startTime
+
+	return b
+}
+
+// Last sets the timeframe to the given duration until the end time. The end time defaults to the time of Build
+// and can be set with EndingAt. It replaces a start time set with StartingAt or Timeframe.
+func (b *SupportArchiveBuilder) Last(duration time.Duration) *SupportArchiveBuilder {
+	if duration <= 0 {
+		b.errs = append(b.errs, fmt.Errorf("timeframe duration %s must be positive", duration))
+	}
+	b.startTime = time.Time{}
+	b.duration = duration
+
+	return b
+}
+
+// LastHours sets the timeframe to the given number of hours until the end time, see Last.
+func (b *SupportArchiveBuilder) LastHours(hours int) *SupportArchiveBuilder {
+	return b.Last(time.Duration(hours) * time.Hour)
+}
+
+// EndingAt sets the end time of the contents.
+func (b *SupportArchiveBuilder) EndingAt(endTime time.Time) *SupportArchiveBuilder {
+	b.endTime = endTime
+
+	return b
+}
+
+// WithLabels adds the labels to the SupportArchive.
+func (b *SupportArchiveBuilder) WithLabels(labels map[string]string) *SupportArchiveBuilder {
+	if b.labels == nil {
+		b.labels = map[string]string{}
+	}
+	maps.Copy(b.labels, labels)
+
+	return b
+}
+
+// WithAnnotations adds the annotations to the SupportArchive.
+func (b *SupportArchiveBuilder) WithAnnotations(annotations map[string]string) *SupportArchiveBuilder {
+	if b.annotations == nil {
+		b.annotations = map[string]string{}
+	}
+	maps.Copy(b.annotations, annotations)
+
+	return b
+}
+
+// RequireApproval lets the SupportArchive wait for the approval of another user than the given requester.
+func (b *SupportArchiveBuilder) RequireApproval(requester string) *SupportArchiveBuilder {
+	b.requester = requester
+	b.approval = true
+
+	return b
+}
+
+// ForTicket references the support ticket the SupportArchive is created for.
+func (b *SupportArchiveBuilder) ForTicket(ticket string) *SupportArchiveBuilder {
+	b.ticket = ticket
+
+	return b
+}
+
+// WithReason describes why the SupportArchive is created.
+func (b *SupportArchiveBuilder) WithReason(reason string) *SupportArchiveBuilder {
+	b.reason = reason
+
+	return b
+}
+
+// WithClock replaces the function that returns the current time, e.g. for tests or schedulers.
+func (b *SupportArchiveBuilder) WithClock(now func() time.Time) *SupportArchiveBuilder {
+	b.now = now
+
+	return b
+}
+
+// Build validates the input and returns the SupportArchive. All validation errors are joined.
+func (b *SupportArchiveBuilder) Build() (*v1.SupportArchive, error) {
+	errs := slices.Clone(b.errs)
+	for _, msg := range validation.IsDNS1123Subdomain(b.name) {
+		errs = append(errs, fmt.Errorf("invalid name %q: %s", b.name, msg))
+	}
+	if b.namespace != "" {
+		for _, msg := range validation.IsDNS1123Label(b.namespace) {
+			errs = append(errs, fmt.Errorf("invalid namespace %q: %s", b.namespace, msg))
+		}
+	}
+	errs = append(errs, validateMetadata(b.labels, b.annotations)...)
+
+	endTime := b.endTime
+	if endTime.IsZero() {
+		endTime = b.now()
+	}
+	startTime := b.startTime
+	if startTime.IsZero() {
+		startTime = endTime.Add(-b.duration)
+	}
+	if !startTime.Before(endTime) {
+		errs = append(errs, fmt.Errorf("start time %s must be before end time %s", startTime.Format(time.RFC3339), endTime.Format(time.RFC3339)))
+	}
+	if !b.includesAny() {
+		errs = append(errs, errors.New("at least one content category must be included"))
+	}
+	if b.approval && b.requester == "" {
+		errs = append(errs, errors.New("the requester must be known if approval is required"))
+	}
+	if len(b.ticket) > maxTicketLength {
+		errs = append(errs, fmt.Errorf("ticket must be no more than %d characters", maxTicketLength))
+	}
+	if len(b.reason) > maxReasonLength {
+		errs = append(errs, fmt.Errorf("reason must be no more than %d characters", maxReasonLength))
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid supportArchive %s: %w", b.name, errors.Join(errs...))
+	}
+
+	return &v1.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        b.name,
+			Namespace:   b.namespace,
+			Labels:      maps.Clone(b.labels),
+			Annotations: maps.Clone(b.annotations),
+		},
+		Spec: v1.SupportArchiveSpec{
+			ExcludedContents: v1.ExcludedContents{
+				SystemState:   b.excluded[v1.ContentSystemState],
+				SensitiveData: b.excluded[v1.ContentSensitiveData],
+				Events:        b.excluded[v1.ContentEvents],
+				Logs:          b.excluded[v1.ContentLogs],
+				VolumeInfo:    b.excluded[v1.ContentVolumeInfo],
+				SystemInfo:    b.excluded[v1.ContentSystemInfo],
+			},
+			ContentTimeframe: v1.ContentTimeframe{
+				StartTime: metav1.NewTime(startTime),
+				EndTime:   metav1.NewTime(endTime),
+			},
+			Requester:        b.requester,
+			RequiresApproval: b.approval,
+			Ticket:           b.ticket,
+			Reason:           b.reason,
+		},
+	}, nil
+}
+
+func (b *SupportArchiveBuilder) setExcluded(category v1.ContentCategory, excluded bool) {
+	if !slices.Contains(allCategories, category) {
+		b.errs = append(b.errs, fmt.Errorf("unknown content category %q", category))
+		return
+	}
+	b.excluded[category] = excluded
+}
+
+func (b *SupportArchiveBuilder) includesAny() bool {
+	for _, category := range allCategories {
+		if !b.excluded[category] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateMetadata(labels map[string]string, annotations map[string]string) []error {
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		value := labels[key]
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Errorf("invalid label key %q: %s", key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			errs = append(errs, fmt.Errorf("invalid value of label %q: %s", key, msg))
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(annotations)) {
+		for _, msg := range validation.IsQualifiedName(key) {
+			errs = append(errs, fmt.Errorf("invalid annotation key %q: %s", key, msg))
+		}
+	}
+
+	return errs
+}
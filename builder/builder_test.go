@@ -0,0 +1,157 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var testNow = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+func testClock() time.Time {
+	return testNow
+}
+
+func TestSupportArchiveBuilder_Build(t *testing.T) {
+	t.Run("should include everything of the last 24 hours by default", func(t *testing.T) {
+		// when
+		archive, err := NewSupportArchive("my-archive", "ecosystem").WithClock(testClock).Build()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "my-archive", archive.Name)
+		assert.Equal(t, "ecosystem", archive.Namespace)
+		assert.Equal(t, v1.ExcludedContents{}, archive.Spec.ExcludedContents)
+		assert.Equal(t, testNow.Add(-24*time.Hour), archive.Spec.ContentTimeframe.StartTime.Time)
+		assert.Equal(t, testNow, archive.Spec.ContentTimeframe.EndTime.Time)
+	})
+	t.Run("should include only the given contents of the last hours", func(t *testing.T) {
+		// when
+		archive, err := NewSupportArchive("my-archive", "ecosystem").
+			IncludeOnly(v1.ContentLogs, v1.ContentEvents).
+			LastHours(6).
+			WithLabels(map[string]string{"app": "ces"}).
+			WithAnnotations(map[string]string{"ticket": "SUP-42"}).
+			WithClock(testClock).
+			Build()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, v1.ExcludedContents{SystemState: true, SensitiveData: true, VolumeInfo: true, SystemInfo: true}, archive.Spec.ExcludedContents)
+		assert.Equal(t, testNow.Add(-6*time.Hour), archive.Spec.ContentTimeframe.StartTime.Time)
+		assert.Equal(t, testNow, archive.Spec.ContentTimeframe.EndTime.Time)
+		assert.Equal(t, map[string]string{"app": "ces"}, archive.Labels)
+		assert.Equal(t, map[string]string{"ticket": "SUP-42"}, archive.Annotations)
+	})
+	t.Run("should exclude the given contents for an absolute timeframe", func(t *testing.T) {
+		// given
+		start := testNow.Add(-time.Hour)
+
+		// when
+		archive, err := NewSupportArchive("my-archive", "").
+			Exclude(v1.ContentSensitiveData).
+			Timeframe(start, testNow).
+			Build()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, v1.ExcludedContents{SensitiveData: true}, archive.Spec.ExcludedContents)
+		assert.Equal(t, start, archive.Spec.ContentTimeframe.StartTime.Time)
+		assert.Equal(t, testNow, archive.Spec.ContentTimeframe.EndTime.Time)
+	})
+	t.Run("should apply the duration until the given end time", func(t *testing.T) {
+		// given
+		end := testNow.Add(-48 * time.Hour)
+
+		// when
+		archive, err := NewSupportArchive("my-archive", "").Last(30 * time.Minute).EndingAt(end).Build()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, end.Add(-30*time.Minute), archive.Spec.ContentTimeframe.StartTime.Time)
+		assert.Equal(t, end, archive.Spec.ContentTimeframe.EndTime.Time)
+	})
+	t.Run("should not share labels with the builder", func(t *testing.T) {
+		// given
+		archiveBuilder := NewSupportArchive("my-archive", "").WithLabels(map[string]string{"app": "ces"})
+		first, err := archiveBuilder.Build()
+		require.NoError(t, err)
+
+		// when
+		second, err := archiveBuilder.WithLabels(map[string]string{"team": "support"}).Build()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"app": "ces"}, first.Labels)
+		assert.Equal(t, map[string]string{"app": "ces", "team": "support"}, second.Labels)
+	})
+	t.Run("should require approval of another user than the requester", func(t *testing.T) {
+		// when
+		archive, err := NewSupportArchive("my-archive", "ecosystem").RequireApproval("alice").Build()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, archive.Spec.RequiresApproval)
+		assert.Equal(t, "alice", archive.Spec.Requester)
+	})
+	t.Run("should fail to require approval without requester", func(t *testing.T) {
+		// when
+		_, err := NewSupportArchive("my-archive", "ecosystem").RequireApproval("").Build()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "the requester must be known if approval is required")
+	})
+	t.Run("should set ticket and reason", func(t *testing.T) {
+		// when
+		archive, err := NewSupportArchive("my-archive", "ecosystem").ForTicket("SUP-1234").WithReason("operator crashes").Build()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "SUP-1234", archive.Spec.Ticket)
+		assert.Equal(t, "operator crashes", archive.Spec.Reason)
+	})
+	t.Run("should fail for too long ticket and reason", func(t *testing.T) {
+		// when
+		_, err := NewSupportArchive("my-archive", "ecosystem").
+			ForTicket(strings.Repeat("a", 129)).
+			WithReason(strings.Repeat("a", 1025)).
+			Build()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "ticket must be no more than 128 characters")
+		assert.ErrorContains(t, err, "reason must be no more than 1024 characters")
+	})
+	t.Run("should fail for swapped start and end time", func(t *testing.T) {
+		// when
+		_, err := NewSupportArchive("my-archive", "").Timeframe(testNow, testNow.Add(-time.Hour)).Build()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "start time 2025-01-02T12:00:00Z must be before end time 2025-01-02T11:00:00Z")
+	})
+	t.Run("should join all validation errors", func(t *testing.T) {
+		// when
+		_, err := NewSupportArchive("My_Archive", "ecosystem").
+			IncludeOnly().
+			Exclude("Metrics").
+			Last(-time.Hour).
+			WithLabels(map[string]string{"app": "not valid"}).
+			Build()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid supportArchive My_Archive")
+		assert.ErrorContains(t, err, "invalid name \"My_Archive\"")
+		assert.ErrorContains(t, err, "unknown content category \"Metrics\"")
+		assert.ErrorContains(t, err, "timeframe duration -1h0m0s must be positive")
+		assert.ErrorContains(t, err, "invalid value of label \"app\"")
+		assert.ErrorContains(t, err, "at least one content category must be included")
+	})
+}
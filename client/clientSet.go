@@ -16,8 +16,9 @@ type clientSet struct {
 }
 
 // NewSupportArchiveClientSet creates a new instance of the support archive client set.
-func NewSupportArchiveClientSet(config *rest.Config) (SupportArchiveEcosystemInterface, error) {
-	clientV1, err := v1.NewForConfig(config)
+// The options are passed to the v1 client.
+func NewSupportArchiveClientSet(config *rest.Config, opts ...v1.Option) (SupportArchiveEcosystemInterface, error) {
+	clientV1, err := v1.NewForConfig(config, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,168 @@
+// Package ctrlclient offers the high-level operations of the support archive client on top of a controller-runtime
+// client, so that operators built with controller-runtime behave like users of the REST client in client/v1.
+package ctrlclient
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+// SupportArchiveInterface offers the high-level operations on supportArchives for a controller-runtime client.
+type SupportArchiveInterface interface {
+	// UpdateStatusWithRetry updates the status of the supportArchive, retrying according to the RetryPolicy.
+	// Before every retry the supportArchive is fetched again and modifyStatusFn is applied to its current status.
+	UpdateStatusWithRetry(ctx context.Context, supportArchive *v1.SupportArchive, modifyStatusFn func(v1.SupportArchiveStatus) v1.SupportArchiveStatus) (*v1.SupportArchive, error)
+	// SetCondition sets the condition in the status of the supportArchive without overwriting other status fields.
+	// The LastTransitionTime is only changed if the status of the condition changes.
+	SetCondition(ctx context.Context, supportArchive *v1.SupportArchive, condition metav1.Condition) (*v1.SupportArchive, error)
+	// AddFinalizer adds the given finalizer to the supportArchive, retrying according to the RetryPolicy.
+	// The passed supportArchive is not modified.
+	AddFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (*v1.SupportArchive, error)
+	// RemoveFinalizer removes the given finalizer from the supportArchive, retrying according to the RetryPolicy.
+	// The passed supportArchive is not modified.
+	RemoveFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (*v1.SupportArchive, error)
+	// HasFinalizer returns true if the supportArchive with the given key currently has the finalizer.
+	HasFinalizer(ctx context.Context, key client.ObjectKey, finalizer string) (bool, error)
+	// Wait waits until the condition of the wait options is true for the supportArchive with the given key, see
+	// clientv1.Wait. It fails with clientv1.ErrCancelled or clientv1.ErrRejected if the supportArchive never gets there.
+	// It returns the last fetched supportArchive, also on errors if there is one.
+	Wait(ctx context.Context, key client.ObjectKey, opts clientv1.WaitOptions) (*v1.SupportArchive, error)
+}
+
+// Option configures the client created by NewSupportArchiveClient.
+type Option func(*supportArchiveClient)
+
+// WithRetryPolicy replaces the clientv1.DefaultRetryPolicy of the client.
+func WithRetryPolicy(policy clientv1.RetryPolicy) Option {
+	return func(client *supportArchiveClient) {
+		client.retryPolicy = policy
+	}
+}
+
+type supportArchiveClient struct {
+	client      client.Client
+	retryPolicy clientv1.RetryPolicy
+}
+
+// NewSupportArchiveClient creates the high-level client for the given controller-runtime client. Its scheme must
+// contain the supportArchive types, see NewScheme.
+func NewSupportArchiveClient(c client.Client, opts ...Option) SupportArchiveInterface {
+	supportArchiveClient := &supportArchiveClient{client: c, retryPolicy: clientv1.DefaultRetryPolicy()}
+	for _, opt := range opts {
+		opt(supportArchiveClient)
+	}
+
+	return supportArchiveClient
+}
+
+func (c *supportArchiveClient) UpdateStatusWithRetry(ctx context.Context, supportArchive *v1.SupportArchive, modifyStatusFn func(v1.SupportArchiveStatus) v1.SupportArchiveStatus) (*v1.SupportArchive, error) {
+	result, err := c.modifyWithRetry(ctx, supportArchive, func(current *v1.SupportArchive) error {
+		current.Status = modifyStatusFn(current.Status)
+		return c.client.Status().Update(ctx, current)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update status of supportArchive %s: %w", supportArchive.Name, err)
+	}
+
+	return result, nil
+}
+
+func (c *supportArchiveClient) SetCondition(ctx context.Context, supportArchive *v1.SupportArchive, condition metav1.Condition) (*v1.SupportArchive, error) {
+	result, err := c.modifyWithRetry(ctx, supportArchive, func(current *v1.SupportArchive) error {
+		patch := client.MergeFromWithOptions(current.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		meta.SetStatusCondition(&current.Status.Conditions, condition)
+		return c.client.Status().Patch(ctx, current, patch)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set condition %s of supportArchive %s: %w", condition.Type, supportArchive.Name, err)
+	}
+
+	return result, nil
+}
+
+func (c *supportArchiveClient) AddFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (*v1.SupportArchive, error) {
+	result, err := c.patchFinalizers(ctx, supportArchive, func(current *v1.SupportArchive) {
+		controllerutil.AddFinalizer(current, finalizer)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add finalizer %s to supportArchive: %w", finalizer, err)
+	}
+
+	return result, nil
+}
+
+func (c *supportArchiveClient) RemoveFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (*v1.SupportArchive, error) {
+	result, err := c.patchFinalizers(ctx, supportArchive, func(current *v1.SupportArchive) {
+		controllerutil.RemoveFinalizer(current, finalizer)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove finalizer %s from supportArchive: %w", finalizer, err)
+	}
+
+	return result, nil
+}
+
+func (c *supportArchiveClient) HasFinalizer(ctx context.Context, key client.ObjectKey, finalizer string) (bool, error) {
+	supportArchive := &v1.SupportArchive{}
+	err := c.client.Get(ctx, key, supportArchive)
+	if err != nil {
+		return false, fmt.Errorf("failed to get supportArchive %s: %w", key.Name, err)
+	}
+
+	return slices.Contains(supportArchive.Finalizers, finalizer), nil
+}
+
+func (c *supportArchiveClient) Wait(ctx context.Context, key client.ObjectKey, opts clientv1.WaitOptions) (*v1.SupportArchive, error) {
+	opts = opts.WithDefaults()
+	current, err := clientv1.Wait(ctx, func(ctx context.Context) (*v1.SupportArchive, error) {
+		supportArchive := &v1.SupportArchive{}
+		err := c.client.Get(ctx, key, supportArchive)
+		return supportArchive, err
+	}, opts)
+	if err != nil {
+		return current, fmt.Errorf("failed to wait for condition %s of supportArchive %s: %w", opts.Condition, key.Name, err)
+	}
+
+	return current, nil
+}
+
+// patchFinalizers patches the finalizers modified by modifyFn. The patch contains the resource version, so that it
+// fails with a conflict and is retried if the finalizers were changed in the meantime.
+func (c *supportArchiveClient) patchFinalizers(ctx context.Context, supportArchive *v1.SupportArchive, modifyFn func(current *v1.SupportArchive)) (*v1.SupportArchive, error) {
+	return c.modifyWithRetry(ctx, supportArchive, func(current *v1.SupportArchive) error {
+		patch := client.MergeFromWithOptions(current.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		modifyFn(current)
+		return c.client.Patch(ctx, current, patch)
+	})
+}
+
+// modifyWithRetry calls modifyFn with a copy of the supportArchive and retries according to the RetryPolicy. Before
+// every retry, and if the passed supportArchive has no resource version, the supportArchive is fetched again.
+func (c *supportArchiveClient) modifyWithRetry(ctx context.Context, supportArchive *v1.SupportArchive, modifyFn func(current *v1.SupportArchive) error) (*v1.SupportArchive, error) {
+	var current *v1.SupportArchive
+	err := c.retryPolicy.Do(ctx, func(ctx context.Context, attempt int) error {
+		current = supportArchive.DeepCopy()
+		if attempt > 1 || supportArchive.ResourceVersion == "" {
+			err := c.client.Get(ctx, client.ObjectKeyFromObject(supportArchive), current)
+			if err != nil {
+				return err
+			}
+		}
+
+		return modifyFn(current)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
@@ -0,0 +1,336 @@
+package ctrlclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	v2 "github.com/cloudogu/k8s-support-archive-lib/api/v2"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+var testCtx = context.Background()
+
+var testKey = client.ObjectKey{Namespace: "ecosystem", Name: "archive"}
+
+func testArchive(finalizers ...string) *v1.SupportArchive {
+	return &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Namespace: testKey.Namespace, Name: testKey.Name, Finalizers: finalizers}}
+}
+
+func testRetryPolicy() clientv1.RetryPolicy {
+	return clientv1.RetryPolicy{Backoff: wait.Backoff{Duration: time.Millisecond, Steps: 3}, MaxAttempts: 3, Retryable: clientv1.IsConflict}
+}
+
+func newFakeClient(t *testing.T, funcs interceptor.Funcs, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme, err := NewScheme()
+	require.NoError(t, err)
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&v1.SupportArchive{}).
+		WithInterceptorFuncs(funcs).
+		Build()
+}
+
+// conflictOnce lets the first call fail with a conflict after changing the supportArchive in the meantime.
+func conflictOnce(change func(supportArchive *v1.SupportArchive)) func(ctx context.Context, c client.Client, obj client.Object) error {
+	conflicted := false
+	return func(ctx context.Context, c client.Client, obj client.Object) error {
+		if conflicted {
+			return nil
+		}
+		conflicted = true
+		current := &v1.SupportArchive{}
+		err := c.Get(ctx, client.ObjectKeyFromObject(obj), current)
+		if err != nil {
+			return err
+		}
+		change(current)
+		err = c.Update(ctx, current)
+		if err != nil {
+			return err
+		}
+
+		return apierrors.NewConflict(v1.GroupVersion.WithResource("supportarchives").GroupResource(), obj.GetName(), nil)
+	}
+}
+
+func TestNewScheme(t *testing.T) {
+	t.Run("should contain all versions of the supportArchive", func(t *testing.T) {
+		// when
+		scheme, err := NewScheme()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, scheme.Recognizes(v1.GroupVersion.WithKind("SupportArchive")))
+		assert.True(t, scheme.Recognizes(v1.GroupVersion.WithKind("SupportArchiveList")))
+		assert.True(t, scheme.Recognizes(v2.GroupVersion.WithKind("SupportArchive")))
+		assert.True(t, scheme.IsGroupRegistered("apps"))
+	})
+}
+
+func Test_supportArchiveClient_UpdateStatusWithRetry(t *testing.T) {
+	t.Run("should update the status", func(t *testing.T) {
+		// given
+		fakeClient := newFakeClient(t, interceptor.Funcs{}, testArchive())
+		sut := NewSupportArchiveClient(fakeClient)
+		supportArchive := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, supportArchive))
+
+		// when
+		result, err := sut.UpdateStatusWithRetry(testCtx, supportArchive, func(status v1.SupportArchiveStatus) v1.SupportArchiveStatus {
+			status.DownloadPath = "/download"
+			return status
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "/download", result.Status.DownloadPath)
+		assert.Empty(t, supportArchive.Status.DownloadPath)
+		stored := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, stored))
+		assert.Equal(t, "/download", stored.Status.DownloadPath)
+	})
+	t.Run("should retry conflicts with the current state", func(t *testing.T) {
+		// given
+		var updates int
+		fakeClient := newFakeClient(t, interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				updates++
+				if updates == 1 {
+					return apierrors.NewConflict(v1.GroupVersion.WithResource("supportarchives").GroupResource(), obj.GetName(), nil)
+				}
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}, testArchive())
+		sut := NewSupportArchiveClient(fakeClient, WithRetryPolicy(testRetryPolicy()))
+		supportArchive := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, supportArchive))
+
+		// when
+		result, err := sut.UpdateStatusWithRetry(testCtx, supportArchive, func(status v1.SupportArchiveStatus) v1.SupportArchiveStatus {
+			status.Errors = append(status.Errors, "failed")
+			return status
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, updates)
+		assert.Equal(t, []string{"failed"}, result.Status.Errors)
+	})
+	t.Run("should fail if the policy is exhausted", func(t *testing.T) {
+		// given
+		fakeClient := newFakeClient(t, interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				return apierrors.NewConflict(v1.GroupVersion.WithResource("supportarchives").GroupResource(), obj.GetName(), nil)
+			},
+		}, testArchive())
+		sut := NewSupportArchiveClient(fakeClient, WithRetryPolicy(testRetryPolicy()))
+
+		// when
+		_, err := sut.UpdateStatusWithRetry(testCtx, testArchive(), func(status v1.SupportArchiveStatus) v1.SupportArchiveStatus {
+			return status
+		})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to update status of supportArchive archive: the maximum number of 3 attempts was reached")
+	})
+}
+
+func Test_supportArchiveClient_SetCondition(t *testing.T) {
+	t.Run("should set the condition and keep the other status fields", func(t *testing.T) {
+		// given
+		existing := testArchive()
+		existing.Status.DownloadPath = "/download"
+		fakeClient := newFakeClient(t, interceptor.Funcs{}, existing)
+		sut := NewSupportArchiveClient(fakeClient)
+		supportArchive := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, supportArchive))
+
+		// when
+		result, err := sut.SetCondition(testCtx, supportArchive, metav1.Condition{Type: v1.ConditionSupportArchiveCreated, Status: metav1.ConditionTrue, Reason: "Created"})
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, meta.IsStatusConditionTrue(result.Status.Conditions, v1.ConditionSupportArchiveCreated))
+		stored := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, stored))
+		assert.Equal(t, "/download", stored.Status.DownloadPath)
+		assert.True(t, meta.IsStatusConditionTrue(stored.Status.Conditions, v1.ConditionSupportArchiveCreated))
+	})
+	t.Run("should retry conflicts without losing concurrent changes", func(t *testing.T) {
+		// given
+		onConflict := conflictOnce(func(supportArchive *v1.SupportArchive) {
+			supportArchive.Labels = map[string]string{"changed": "true"}
+		})
+		fakeClient := newFakeClient(t, interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				err := onConflict(ctx, c, obj)
+				if err != nil {
+					return err
+				}
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}, testArchive())
+		sut := NewSupportArchiveClient(fakeClient, WithRetryPolicy(testRetryPolicy()))
+		supportArchive := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, supportArchive))
+
+		// when
+		result, err := sut.SetCondition(testCtx, supportArchive, metav1.Condition{Type: v1.ConditionSecretsFetched, Status: metav1.ConditionFalse, Reason: "Failed"})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "true", result.Labels["changed"])
+		assert.True(t, meta.IsStatusConditionFalse(result.Status.Conditions, v1.ConditionSecretsFetched))
+	})
+}
+
+func Test_supportArchiveClient_AddFinalizer(t *testing.T) {
+	t.Run("should add the finalizer without modifying the passed object", func(t *testing.T) {
+		// given
+		fakeClient := newFakeClient(t, interceptor.Funcs{}, testArchive("existing"))
+		sut := NewSupportArchiveClient(fakeClient)
+		supportArchive := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, supportArchive))
+
+		// when
+		result, err := sut.AddFinalizer(testCtx, supportArchive, "cleanup")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"existing", "cleanup"}, result.Finalizers)
+		assert.Equal(t, []string{"existing"}, supportArchive.Finalizers)
+		hasFinalizer, err := sut.HasFinalizer(testCtx, testKey, "cleanup")
+		require.NoError(t, err)
+		assert.True(t, hasFinalizer)
+	})
+	t.Run("should retry conflicts and keep concurrently added finalizers", func(t *testing.T) {
+		// given
+		onConflict := conflictOnce(func(supportArchive *v1.SupportArchive) {
+			supportArchive.Finalizers = append(supportArchive.Finalizers, "concurrent")
+		})
+		fakeClient := newFakeClient(t, interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				err := onConflict(ctx, c, obj)
+				if err != nil {
+					return err
+				}
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+		}, testArchive())
+		sut := NewSupportArchiveClient(fakeClient, WithRetryPolicy(testRetryPolicy()))
+		supportArchive := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, supportArchive))
+
+		// when
+		result, err := sut.AddFinalizer(testCtx, supportArchive, "cleanup")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"concurrent", "cleanup"}, result.Finalizers)
+	})
+	t.Run("should fail if the supportArchive does not exist", func(t *testing.T) {
+		// given
+		fakeClient := newFakeClient(t, interceptor.Funcs{})
+		sut := NewSupportArchiveClient(fakeClient)
+
+		// when
+		_, err := sut.AddFinalizer(testCtx, testArchive(), "cleanup")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.ErrorContains(t, err, "failed to add finalizer cleanup to supportArchive")
+	})
+}
+
+func Test_supportArchiveClient_RemoveFinalizer(t *testing.T) {
+	t.Run("should remove the finalizer", func(t *testing.T) {
+		// given
+		fakeClient := newFakeClient(t, interceptor.Funcs{}, testArchive("cleanup", "other"))
+		sut := NewSupportArchiveClient(fakeClient)
+		supportArchive := &v1.SupportArchive{}
+		require.NoError(t, fakeClient.Get(testCtx, testKey, supportArchive))
+
+		// when
+		result, err := sut.RemoveFinalizer(testCtx, supportArchive, "cleanup")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"other"}, result.Finalizers)
+		hasFinalizer, err := sut.HasFinalizer(testCtx, testKey, "cleanup")
+		require.NoError(t, err)
+		assert.False(t, hasFinalizer)
+	})
+}
+
+func Test_supportArchiveClient_Wait(t *testing.T) {
+	t.Run("should return once the condition is true", func(t *testing.T) {
+		// given
+		var gets int
+		fakeClient := newFakeClient(t, interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				err := c.Get(ctx, key, obj, opts...)
+				if err == nil && gets == 2 {
+					meta.SetStatusCondition(&obj.(*v1.SupportArchive).Status.Conditions, metav1.Condition{Type: v1.ConditionSupportArchiveCreated, Status: metav1.ConditionTrue})
+				}
+				return err
+			},
+		}, testArchive())
+		sut := NewSupportArchiveClient(fakeClient)
+
+		// when
+		result, err := sut.Wait(testCtx, testKey, clientv1.WaitOptions{Interval: time.Millisecond, Timeout: time.Second})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, gets)
+		assert.True(t, meta.IsStatusConditionTrue(result.Status.Conditions, v1.ConditionSupportArchiveCreated))
+	})
+	t.Run("should fail on timeout", func(t *testing.T) {
+		// given
+		fakeClient := newFakeClient(t, interceptor.Funcs{}, testArchive())
+		sut := NewSupportArchiveClient(fakeClient)
+
+		// when
+		result, err := sut.Wait(testCtx, testKey, clientv1.WaitOptions{Condition: v1.ConditionSecretsFetched, Interval: time.Millisecond, Timeout: 10 * time.Millisecond})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to wait for condition SecretsFetched of supportArchive archive")
+		require.NotNil(t, result)
+		assert.Equal(t, testKey.Name, result.Name)
+	})
+	t.Run("should fail once cancelled", func(t *testing.T) {
+		// given
+		archive := testArchive()
+		archive.Status.Phase = v1.StatusPhaseCancelled
+		fakeClient := newFakeClient(t, interceptor.Funcs{}, archive)
+		sut := NewSupportArchiveClient(fakeClient)
+
+		// when
+		result, err := sut.Wait(testCtx, testKey, clientv1.WaitOptions{Interval: time.Millisecond, Timeout: time.Second})
+
+		// then
+		require.ErrorIs(t, err, clientv1.ErrCancelled)
+		require.NotNil(t, result)
+		assert.Equal(t, v1.StatusPhaseCancelled, result.Status.Phase)
+	})
+}
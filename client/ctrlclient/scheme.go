@@ -0,0 +1,41 @@
+package ctrlclient
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	v2 "github.com/cloudogu/k8s-support-archive-lib/api/v2"
+)
+
+// AddToScheme adds all API versions of the supportArchive to the scheme.
+func AddToScheme(scheme *runtime.Scheme) error {
+	err := v1.AddToScheme(scheme)
+	if err != nil {
+		return fmt.Errorf("failed to add supportArchive v1 to scheme: %w", err)
+	}
+	err = v2.AddToScheme(scheme)
+	if err != nil {
+		return fmt.Errorf("failed to add supportArchive v2 to scheme: %w", err)
+	}
+
+	return nil
+}
+
+// NewScheme returns a scheme with the Kubernetes built-in types and all API versions of the supportArchive, ready to
+// be used for a controller-runtime client or manager.
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(scheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add client-go types to scheme: %w", err)
+	}
+	err = AddToScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return scheme, nil
+}
@@ -0,0 +1,197 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+// MultiClusterInterface creates and awaits the same SupportArchive in several clusters.
+type MultiClusterInterface interface {
+	// Clusters returns the sorted names of all clusters.
+	Clusters() []string
+	// Cluster returns the client set for the cluster with the given name.
+	Cluster(name string) (SupportArchiveEcosystemInterface, bool)
+	// Create creates the supportArchive in the given namespace of every cluster in parallel.
+	Create(ctx context.Context, namespace string, supportArchive *v1.SupportArchive, opts metav1.CreateOptions) MultiClusterResult
+	// Wait waits in parallel until the supportArchive with the given name fulfills the wait condition in every cluster.
+	// Clusters in which the supportArchive is cancelled or rejected fail with clientv1.ErrCancelled or
+	// clientv1.ErrRejected right away.
+	Wait(ctx context.Context, namespace string, name string, opts WaitOptions) MultiClusterResult
+	// CreateAndWait creates the supportArchive in every cluster and waits until it fulfills the wait condition.
+	CreateAndWait(ctx context.Context, namespace string, supportArchive *v1.SupportArchive, opts WaitOptions) MultiClusterResult
+}
+
+// WaitOptions configure how to wait for SupportArchives. It is an alias of clientv1.WaitOptions, so that all clients
+// wait alike.
+type WaitOptions = clientv1.WaitOptions
+
+// ClusterResult contains the outcome of an operation in a single cluster.
+type ClusterResult struct {
+	// Cluster is the name of the cluster.
+	Cluster string
+	// SupportArchive is the last known state of the supportArchive in the cluster. It may be nil on errors.
+	SupportArchive *v1.SupportArchive
+	// Err is the error that occurred in the cluster, if any.
+	Err error
+}
+
+// MultiClusterResult aggregates the results of all clusters.
+type MultiClusterResult struct {
+	// Results contains one result per cluster sorted by cluster name.
+	Results []ClusterResult
+}
+
+// Err joins the errors of all clusters. It returns nil if the operation succeeded in every cluster.
+func (r MultiClusterResult) Err() error {
+	var errs []error
+	for _, result := range r.Results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", result.Cluster, result.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Failed returns the results of all clusters with errors.
+func (r MultiClusterResult) Failed() []ClusterResult {
+	var failed []ClusterResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
+
+type multiClusterClient struct {
+	clusters map[string]SupportArchiveEcosystemInterface
+}
+
+// NewMultiClusterClient creates a client for the clusters given as configs by cluster name.
+func NewMultiClusterClient(configs map[string]*rest.Config) (MultiClusterInterface, error) {
+	clientSets := make(map[string]SupportArchiveEcosystemInterface, len(configs))
+	for name, config := range configs {
+		clientSet, err := NewSupportArchiveClientSet(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client for cluster %s: %w", name, err)
+		}
+		clientSets[name] = clientSet
+	}
+
+	return NewMultiClusterClientForClientSets(clientSets), nil
+}
+
+// NewMultiClusterClientForClientSets creates a client for the given client sets by cluster name.
+func NewMultiClusterClientForClientSets(clientSets map[string]SupportArchiveEcosystemInterface) MultiClusterInterface {
+	return &multiClusterClient{clusters: clientSets}
+}
+
+// ConfigsForContexts loads the rest.Config of each given kubeconfig context, keyed by the context name.
+// An empty kubeconfig path uses the default loading rules.
+func ConfigsForContexts(kubeconfig string, contexts ...string) (map[string]*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+
+	configs := make(map[string]*rest.Config, len(contexts))
+	for _, kubeContext := range contexts {
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+		config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config for context %s: %w", kubeContext, err)
+		}
+		configs[kubeContext] = config
+	}
+
+	return configs, nil
+}
+
+// Clusters returns the sorted names of all clusters.
+func (m *multiClusterClient) Clusters() []string {
+	names := make([]string, 0, len(m.clusters))
+	for name := range m.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Cluster returns the client set for the cluster with the given name.
+func (m *multiClusterClient) Cluster(name string) (SupportArchiveEcosystemInterface, bool) {
+	clientSet, ok := m.clusters[name]
+	return clientSet, ok
+}
+
+// Create creates the supportArchive in the given namespace of every cluster in parallel.
+func (m *multiClusterClient) Create(ctx context.Context, namespace string, supportArchive *v1.SupportArchive, opts metav1.CreateOptions) MultiClusterResult {
+	return m.forEachCluster(func(name string, clientSet SupportArchiveEcosystemInterface) ClusterResult {
+		created, err := clientSet.SupportArchiveV1().SupportArchives(namespace).Create(ctx, supportArchive.DeepCopy(), opts)
+		if err != nil {
+			return ClusterResult{Cluster: name, Err: fmt.Errorf("failed to create supportArchive %s: %w", supportArchive.Name, err)}
+		}
+
+		return ClusterResult{Cluster: name, SupportArchive: created}
+	})
+}
+
+// Wait waits in parallel until the supportArchive with the given name fulfills the wait condition in every cluster.
+func (m *multiClusterClient) Wait(ctx context.Context, namespace string, name string, opts WaitOptions) MultiClusterResult {
+	return m.forEachCluster(func(cluster string, clientSet SupportArchiveEcosystemInterface) ClusterResult {
+		return waitInCluster(ctx, cluster, clientSet, namespace, name, opts)
+	})
+}
+
+// CreateAndWait creates the supportArchive in every cluster and waits until it fulfills the wait condition.
+// Clusters in which the creation failed are not waited for.
+func (m *multiClusterClient) CreateAndWait(ctx context.Context, namespace string, supportArchive *v1.SupportArchive, opts WaitOptions) MultiClusterResult {
+	return m.forEachCluster(func(cluster string, clientSet SupportArchiveEcosystemInterface) ClusterResult {
+		_, err := clientSet.SupportArchiveV1().SupportArchives(namespace).Create(ctx, supportArchive.DeepCopy(), metav1.CreateOptions{})
+		if err != nil {
+			return ClusterResult{Cluster: cluster, Err: fmt.Errorf("failed to create supportArchive %s: %w", supportArchive.Name, err)}
+		}
+
+		return waitInCluster(ctx, cluster, clientSet, namespace, supportArchive.Name, opts)
+	})
+}
+
+func (m *multiClusterClient) forEachCluster(fn func(name string, clientSet SupportArchiveEcosystemInterface) ClusterResult) MultiClusterResult {
+	names := m.Clusters()
+	results := make([]ClusterResult, len(names))
+
+	wg := sync.WaitGroup{}
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = fn(name, m.clusters[name])
+		}()
+	}
+	wg.Wait()
+
+	return MultiClusterResult{Results: results}
+}
+
+func waitInCluster(ctx context.Context, cluster string, clientSet SupportArchiveEcosystemInterface, namespace string, name string, opts WaitOptions) ClusterResult {
+	opts = opts.WithDefaults()
+	supportArchives := clientSet.SupportArchiveV1().SupportArchives(namespace)
+	current, err := clientv1.Wait(ctx, func(ctx context.Context) (*v1.SupportArchive, error) {
+		return supportArchives.Get(ctx, name, metav1.GetOptions{})
+	}, opts)
+	if err != nil {
+		return ClusterResult{Cluster: cluster, SupportArchive: current, Err: fmt.Errorf("failed to wait for condition %s of supportArchive %s: %w", opts.Condition, name, err)}
+	}
+
+	return ClusterResult{Cluster: cluster, SupportArchive: current}
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+var testCtx = context.Background()
+
+func newClusterServer(t *testing.T, createStatus int, ready bool) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Add("content-type", "application/json")
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "incident", Namespace: "ecosystem"}}
+
+		switch request.Method {
+		case http.MethodPost:
+			assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/ecosystem/supportarchives", request.URL.Path)
+			writer.WriteHeader(createStatus)
+		case http.MethodGet:
+			assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/ecosystem/supportarchives/incident", request.URL.Path)
+			if ready {
+				supportArchive.Status.Conditions = []metav1.Condition{{Type: v1.ConditionSupportArchiveCreated, Status: metav1.ConditionTrue}}
+			}
+		}
+
+		bytes, err := json.Marshal(supportArchive)
+		require.NoError(t, err)
+		_, err = writer.Write(bytes)
+		require.NoError(t, err)
+	}))
+}
+
+func TestNewMultiClusterClient(t *testing.T) {
+	t.Run("should create client for all clusters", func(t *testing.T) {
+		// when
+		client, err := NewMultiClusterClient(map[string]*rest.Config{"production": {}, "staging": {}})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"production", "staging"}, client.Clusters())
+		_, ok := client.Cluster("staging")
+		assert.True(t, ok)
+	})
+	t.Run("should fail for invalid config", func(t *testing.T) {
+		// when
+		_, err := NewMultiClusterClient(map[string]*rest.Config{"broken": {Host: "foo:/error"}})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to create client for cluster broken")
+	})
+}
+
+func Test_multiClusterClient_CreateAndWait(t *testing.T) {
+	t.Run("should create and wait in all clusters", func(t *testing.T) {
+		// given
+		staging := newClusterServer(t, http.StatusCreated, true)
+		defer staging.Close()
+		production := newClusterServer(t, http.StatusCreated, true)
+		defer production.Close()
+		client, err := NewMultiClusterClient(map[string]*rest.Config{"staging": {Host: staging.URL}, "production": {Host: production.URL}})
+		require.NoError(t, err)
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "incident"}}
+
+		// when
+		result := client.CreateAndWait(testCtx, "ecosystem", supportArchive, WaitOptions{Interval: 10 * time.Millisecond})
+
+		// then
+		require.NoError(t, result.Err())
+		require.Len(t, result.Results, 2)
+		assert.Equal(t, "production", result.Results[0].Cluster)
+		assert.Equal(t, "staging", result.Results[1].Cluster)
+		assert.Equal(t, "incident", result.Results[0].SupportArchive.Name)
+		assert.Empty(t, result.Failed())
+	})
+	t.Run("should return per cluster errors", func(t *testing.T) {
+		// given
+		staging := newClusterServer(t, http.StatusCreated, false)
+		defer staging.Close()
+		production := newClusterServer(t, http.StatusForbidden, true)
+		defer production.Close()
+		client, err := NewMultiClusterClient(map[string]*rest.Config{"staging": {Host: staging.URL}, "production": {Host: production.URL}})
+		require.NoError(t, err)
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "incident"}}
+
+		// when
+		result := client.CreateAndWait(testCtx, "ecosystem", supportArchive, WaitOptions{Interval: 10 * time.Millisecond, Timeout: 50 * time.Millisecond})
+
+		// then
+		err = result.Err()
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "cluster production: failed to create supportArchive incident")
+		assert.ErrorContains(t, err, "cluster staging: failed to wait for condition Created of supportArchive incident")
+		require.Len(t, result.Failed(), 2)
+		assert.NotNil(t, result.Results[1].SupportArchive)
+	})
+}
+
+func Test_multiClusterClient_Wait(t *testing.T) {
+	t.Run("should fail right away for rejected supportArchives", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Add("content-type", "application/json")
+			require.NoError(t, json.NewEncoder(writer).Encode(&v1.SupportArchive{
+				ObjectMeta: metav1.ObjectMeta{Name: "incident", Namespace: "ecosystem"},
+				Status:     v1.SupportArchiveStatus{Approval: &v1.ApprovalStatus{Decision: v1.ApprovalDecisionRejected, DecidedBy: "bob"}},
+			}))
+		}))
+		defer server.Close()
+		client, err := NewMultiClusterClient(map[string]*rest.Config{"staging": {Host: server.URL}})
+		require.NoError(t, err)
+
+		// when
+		result := client.Wait(testCtx, "ecosystem", "incident", WaitOptions{Interval: 10 * time.Millisecond, Timeout: time.Minute})
+
+		// then
+		err = result.Err()
+		require.ErrorIs(t, err, clientv1.ErrRejected)
+		assert.ErrorContains(t, err, "cluster staging: failed to wait for condition Created of supportArchive incident: the supportArchive was rejected by bob")
+		assert.Equal(t, "incident", result.Results[0].SupportArchive.Name)
+	})
+}
+
+func Test_multiClusterClient_Create(t *testing.T) {
+	// given
+	staging := newClusterServer(t, http.StatusCreated, false)
+	defer staging.Close()
+	client, err := NewMultiClusterClient(map[string]*rest.Config{"staging": {Host: staging.URL}})
+	require.NoError(t, err)
+
+	// when
+	result := client.Create(testCtx, "ecosystem", &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "incident"}}, metav1.CreateOptions{})
+
+	// then
+	require.NoError(t, result.Err())
+	assert.Equal(t, "incident", result.Results[0].SupportArchive.Name)
+}
+
+func TestConfigsForContexts(t *testing.T) {
+	// given
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	content := `apiVersion: v1
+kind: Config
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com
+- name: production
+  cluster:
+    server: https://production.example.com
+contexts:
+- name: staging
+  context:
+    cluster: staging
+- name: production
+  context:
+    cluster: production
+current-context: staging
+`
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(content), 0o600))
+
+	t.Run("should load config per context", func(t *testing.T) {
+		// when
+		configs, err := ConfigsForContexts(kubeconfig, "staging", "production")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://staging.example.com", configs["staging"].Host)
+		assert.Equal(t, "https://production.example.com", configs["production"].Host)
+	})
+	t.Run("should fail for unknown context", func(t *testing.T) {
+		// when
+		_, err := ConfigsForContexts(kubeconfig, "unknown")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, fmt.Sprintf("failed to load config for context %s", "unknown"))
+	})
+}
@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var (
+	// ErrSelfApproval is returned if the requester of a supportArchive tries to approve it.
+	ErrSelfApproval = errors.New("the requester cannot approve their own supportArchive")
+	// ErrUnknownRequester is returned if a supportArchive without requester is approved, because it cannot be
+	// ensured that another user approves it.
+	ErrUnknownRequester = errors.New("the requester of the supportArchive is unknown")
+	// ErrApprovalNotRequired is returned if a supportArchive that does not require approval is approved or rejected.
+	ErrApprovalNotRequired = errors.New("the supportArchive does not require approval")
+	// ErrAlreadyDecided is returned if a supportArchive is approved or rejected for the second time.
+	ErrAlreadyDecided = errors.New("the supportArchive was already approved or rejected")
+)
+
+// Approve records in the approval annotations of the supportArchive with the given name that the current user
+// approved it. The current user is determined by a SelfSubjectReview and must differ from the requester in the spec.
+// The approval webhook enforces the same rules on the server and the operator copies the approval to the status.
+func (client *supportArchiveClient) Approve(ctx context.Context, name string, reason string) (result *v1.SupportArchive, err error) {
+	ctx, span := client.startSpan(ctx, "Approve", name)
+	defer func() { endSpan(span, err) }()
+
+	result, err = client.decide(ctx, name, v1.ApprovalDecisionApproved, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve supportArchive %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// Reject records in the approval annotations of the supportArchive with the given name that the current user
+// rejected it.
+// Unlike approvals, the requester may reject their own supportArchive.
+func (client *supportArchiveClient) Reject(ctx context.Context, name string, reason string) (result *v1.SupportArchive, err error) {
+	ctx, span := client.startSpan(ctx, "Reject", name)
+	defer func() { endSpan(span, err) }()
+
+	result, err = client.decide(ctx, name, v1.ApprovalDecisionRejected, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reject supportArchive %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (client *supportArchiveClient) decide(ctx context.Context, name string, decision v1.ApprovalDecision, reason string) (*v1.SupportArchive, error) {
+	user, err := currentUser(ctx, client.reviews)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.patchWithRetry(ctx, name, func(current *v1.SupportArchive) (archivePatch, error) {
+		err := CheckDecision(current, user, decision)
+		if err != nil {
+			return archivePatch{}, err
+		}
+
+		approval := v1.ApprovalStatus{Decision: decision, DecidedBy: user, DecidedAt: metav1.Now(), Reason: reason}
+		return archivePatch{patchType: types.MergePatchType, data: map[string]any{
+			"metadata": map[string]any{
+				"resourceVersion": current.ResourceVersion,
+				"annotations":     v1.ApprovalAnnotations(approval),
+			},
+		}}, nil
+	})
+}
+
+// CheckDecision returns an error if the user must not make the decision about the current supportArchive, e.g.
+// because the user is its requester or it was already decided in the status or the approval annotations.
+func CheckDecision(current *v1.SupportArchive, user string, decision v1.ApprovalDecision) error {
+	if !current.Spec.RequiresApproval {
+		return ErrApprovalNotRequired
+	}
+	if current.Status.Approval != nil {
+		return fmt.Errorf("%w: %s by %s", ErrAlreadyDecided, current.Status.Approval.Decision, current.Status.Approval.DecidedBy)
+	}
+	if v1.HasApprovalAnnotations(current.Annotations) {
+		return fmt.Errorf("%w: %s by %s", ErrAlreadyDecided, current.Annotations[v1.AnnotationApprovalDecision], current.Annotations[v1.AnnotationApprovalDecidedBy])
+	}
+	if decision != v1.ApprovalDecisionApproved {
+		return nil
+	}
+	if current.Spec.Requester == "" {
+		return ErrUnknownRequester
+	}
+	if current.Spec.Requester == user {
+		return ErrSelfApproval
+	}
+
+	return nil
+}
+
+// currentUser returns the name of the user that the API server authenticates the requests of the client as.
+func currentUser(ctx context.Context, reviews authenticationv1client.SelfSubjectReviewInterface) (string, error) {
+	review, err := reviews.Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current user: %w", err)
+	}
+	if review.Status.UserInfo.Username == "" {
+		return "", errors.New("failed to determine current user: the API server returned no username")
+	}
+
+	return review.Status.UserInfo.Username, nil
+}
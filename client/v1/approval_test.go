@@ -0,0 +1,189 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+func archiveRequiringApproval(requester string) *v1.SupportArchive {
+	return &v1.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"},
+		Spec:       v1.SupportArchiveSpec{Requester: requester, RequiresApproval: true},
+	}
+}
+
+func Test_supportArchiveClient_Approve(t *testing.T) {
+	t.Run("should record approval of another user", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveRequiringApproval("alice"))
+		defer server.Close()
+		fake.user = "bob"
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.Approve(testCtx, "mySupportArchive", "ticket checked")
+
+		// then
+		require.NoError(t, err)
+		approval, err := v1.ApprovalFromAnnotations(result.Annotations)
+		require.NoError(t, err)
+		require.NotNil(t, approval)
+		assert.Equal(t, v1.ApprovalDecisionApproved, approval.Decision)
+		assert.Equal(t, "bob", approval.DecidedBy)
+		assert.Equal(t, "ticket checked", approval.Reason)
+		assert.False(t, approval.DecidedAt.IsZero())
+		assert.Equal(t, result.Annotations, fake.objects["mySupportArchive"].Annotations)
+		assert.Nil(t, fake.objects["mySupportArchive"].Status.Approval)
+	})
+	t.Run("should fail if the requester approves", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveRequiringApproval("alice"))
+		defer server.Close()
+		fake.user = "alice"
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Approve(testCtx, "mySupportArchive", "")
+
+		// then
+		require.ErrorIs(t, err, ErrSelfApproval)
+		assert.EqualError(t, err, "failed to approve supportArchive mySupportArchive: the requester cannot approve their own supportArchive")
+		assert.Zero(t, fake.patches)
+	})
+	t.Run("should fail if the requester is unknown", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveRequiringApproval(""))
+		defer server.Close()
+		fake.user = "bob"
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Approve(testCtx, "mySupportArchive", "")
+
+		// then
+		require.ErrorIs(t, err, ErrUnknownRequester)
+	})
+	t.Run("should fail if no approval is required", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+		defer server.Close()
+		fake.user = "bob"
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Approve(testCtx, "mySupportArchive", "")
+
+		// then
+		require.ErrorIs(t, err, ErrApprovalNotRequired)
+	})
+	t.Run("should fail if rejected concurrently", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveRequiringApproval("alice"))
+		defer server.Close()
+		fake.user = "bob"
+		fake.onPatch = func(name string) {
+			fake.objects[name].Status.Approval = &v1.ApprovalStatus{Decision: v1.ApprovalDecisionRejected, DecidedBy: "carol"}
+		}
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Approve(testCtx, "mySupportArchive", "")
+
+		// then
+		require.ErrorIs(t, err, ErrAlreadyDecided)
+		assert.ErrorContains(t, err, "Rejected by carol")
+		assert.Equal(t, 1, fake.patches)
+		assert.Equal(t, "carol", fake.objects["mySupportArchive"].Status.Approval.DecidedBy)
+	})
+	t.Run("should fail if decided in the annotations concurrently", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveRequiringApproval("alice"))
+		defer server.Close()
+		fake.user = "bob"
+		fake.onPatch = func(name string) {
+			fake.objects[name].Annotations = v1.ApprovalAnnotations(v1.ApprovalStatus{Decision: v1.ApprovalDecisionRejected, DecidedBy: "carol", DecidedAt: metav1.Now()})
+		}
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Approve(testCtx, "mySupportArchive", "")
+
+		// then
+		require.ErrorIs(t, err, ErrAlreadyDecided)
+		assert.ErrorContains(t, err, "Rejected by carol")
+		assert.Equal(t, 1, fake.patches)
+	})
+	t.Run("should fail if the current user cannot be determined", func(t *testing.T) {
+		// given
+		_, server := newFakeServer(t, archiveRequiringApproval("alice"))
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Approve(testCtx, "mySupportArchive", "")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to determine current user: the API server returned no username")
+	})
+}
+
+func Test_supportArchiveClient_Reject(t *testing.T) {
+	t.Run("should let the requester reject their own supportArchive", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveRequiringApproval("alice"))
+		defer server.Close()
+		fake.user = "alice"
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.Reject(testCtx, "mySupportArchive", "not needed anymore")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Rejected", result.Annotations[v1.AnnotationApprovalDecision])
+		assert.Equal(t, "alice", result.Annotations[v1.AnnotationApprovalDecidedBy])
+		assert.Equal(t, "not needed anymore", result.Annotations[v1.AnnotationApprovalReason])
+	})
+	t.Run("should fail if already approved", func(t *testing.T) {
+		// given
+		archive := archiveRequiringApproval("alice")
+		archive.Status.Approval = &v1.ApprovalStatus{Decision: v1.ApprovalDecisionApproved, DecidedBy: "bob"}
+		fake, server := newFakeServer(t, archive)
+		defer server.Close()
+		fake.user = "carol"
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Reject(testCtx, "mySupportArchive", "")
+
+		// then
+		require.ErrorIs(t, err, ErrAlreadyDecided)
+		assert.EqualError(t, err, "failed to reject supportArchive mySupportArchive: the supportArchive was already approved or rejected: Approved by bob")
+	})
+}
+
+func Test_client_CurrentUser(t *testing.T) {
+	t.Run("should return the user of the SelfSubjectReview", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t)
+		defer server.Close()
+		fake.user = "system:serviceaccount:ecosystem:operator"
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		user, err := client.CurrentUser(testCtx)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "system:serviceaccount:ecosystem:operator", user)
+	})
+}
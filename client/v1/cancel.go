@@ -0,0 +1,80 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// cancelPollInterval is the interval in which Cancel checks whether the cancellation was acknowledged.
+const cancelPollInterval = time.Second
+
+// ErrAlreadyCreated is returned by Cancel if the supportArchive was created before the operator could stop it.
+var ErrAlreadyCreated = errors.New("the supportArchive was already created")
+
+// Cancel sets Cancel in the spec of the supportArchive with the given name and waits until the operator acknowledges
+// the cancellation with the Cancelled condition or phase. It returns ErrAlreadyCreated if the supportArchive is or
+// becomes ready before that, and ErrRejected if it was rejected. Like Wait, it waits 30 minutes at most. Limit the
+// wait further with the deadline of the context.
+func (client *supportArchiveClient) Cancel(ctx context.Context, name string) (result *v1.SupportArchive, err error) {
+	ctx, span := client.startSpan(ctx, "Cancel", name)
+	defer func() { endSpan(span, err) }()
+
+	result, err = client.cancel(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel supportArchive %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+func (client *supportArchiveClient) cancel(ctx context.Context, name string) (*v1.SupportArchive, error) {
+	current, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !current.Spec.Cancel {
+		err = checkCancellation(current)
+		if err != nil {
+			return nil, err
+		}
+		_, err = client.Patch(ctx, name, types.MergePatchType, []byte(`{"spec":{"cancel":true}}`), metav1.PatchOptions{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	get := func(ctx context.Context) (*v1.SupportArchive, error) {
+		supportArchive, getErr := client.Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil || meta.IsStatusConditionTrue(supportArchive.Status.Conditions, v1.ConditionCancelled) {
+			return supportArchive, getErr
+		}
+		return supportArchive, checkCancellation(supportArchive)
+	}
+	current, err = Wait(ctx, get, WaitOptions{Condition: v1.ConditionCancelled, Interval: cancelPollInterval})
+	if errors.Is(err, ErrCancelled) {
+		// the operator already set the Cancelled phase, but not yet the condition
+		return current, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for condition %s: %w", v1.ConditionCancelled, err)
+	}
+
+	return current, nil
+}
+
+// checkCancellation returns an error if the current supportArchive can no longer be cancelled.
+func checkCancellation(current *v1.SupportArchive) error {
+	if current.Status.Phase == v1.StatusPhaseCreated || meta.IsStatusConditionTrue(current.Status.Conditions, v1.ConditionSupportArchiveCreated) {
+		return ErrAlreadyCreated
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+func archiveWithCondition(conditionType string) *v1.SupportArchive {
+	return &v1.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"},
+		Status:     v1.SupportArchiveStatus{Conditions: []metav1.Condition{{Type: conditionType, Status: metav1.ConditionTrue}}},
+	}
+}
+
+func Test_supportArchiveClient_Cancel(t *testing.T) {
+	t.Run("should set cancel and wait for acknowledgement", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveWithCondition(v1.ConditionNodeInfoFetched))
+		defer server.Close()
+		fake.onPatch = func(name string) {
+			// the operator acknowledges the cancellation immediately
+			fake.objects[name].Status.Conditions = append(fake.objects[name].Status.Conditions, metav1.Condition{Type: v1.ConditionCancelled, Status: metav1.ConditionTrue})
+		}
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.Cancel(testCtx, "mySupportArchive")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Spec.Cancel)
+		assert.True(t, fake.objects["mySupportArchive"].Spec.Cancel)
+		assert.Equal(t, 1, fake.patches)
+	})
+	t.Run("should not patch again if already cancelled", func(t *testing.T) {
+		// given
+		archive := archiveWithCondition(v1.ConditionCancelled)
+		archive.Spec.Cancel = true
+		fake, server := newFakeServer(t, archive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.Cancel(testCtx, "mySupportArchive")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Spec.Cancel)
+		assert.Zero(t, fake.patches)
+	})
+	t.Run("should fail if already created", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveWithCondition(v1.ConditionSupportArchiveCreated))
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Cancel(testCtx, "mySupportArchive")
+
+		// then
+		require.ErrorIs(t, err, ErrAlreadyCreated)
+		assert.EqualError(t, err, "failed to cancel supportArchive mySupportArchive: the supportArchive was already created")
+		assert.Zero(t, fake.patches)
+	})
+	t.Run("should fail if created before acknowledgement", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveWithCondition(v1.ConditionNodeInfoFetched))
+		defer server.Close()
+		fake.onPatch = func(name string) {
+			fake.objects[name].Status.Conditions = append(fake.objects[name].Status.Conditions, metav1.Condition{Type: v1.ConditionSupportArchiveCreated, Status: metav1.ConditionTrue})
+		}
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Cancel(testCtx, "mySupportArchive")
+
+		// then
+		require.ErrorIs(t, err, ErrAlreadyCreated)
+		assert.EqualError(t, err, "failed to cancel supportArchive mySupportArchive: failed to wait for condition Cancelled: the supportArchive was already created")
+	})
+	t.Run("should succeed if already in the cancelled phase", func(t *testing.T) {
+		// given
+		archive := archiveWithCondition(v1.ConditionNodeInfoFetched)
+		archive.Status.Phase = v1.StatusPhaseCancelled
+		fake, server := newFakeServer(t, archive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.Cancel(testCtx, "mySupportArchive")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, v1.StatusPhaseCancelled, result.Status.Phase)
+		assert.Equal(t, 1, fake.patches)
+	})
+	t.Run("should fail if already in the created phase", func(t *testing.T) {
+		// given
+		archive := archiveWithCondition(v1.ConditionNodeInfoFetched)
+		archive.Status.Phase = v1.StatusPhaseCreated
+		fake, server := newFakeServer(t, archive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Cancel(testCtx, "mySupportArchive")
+
+		// then
+		require.ErrorIs(t, err, ErrAlreadyCreated)
+		assert.Zero(t, fake.patches)
+	})
+	t.Run("should fail if rejected", func(t *testing.T) {
+		// given
+		archive := archiveWithCondition(v1.ConditionNodeInfoFetched)
+		archive.Status.Approval = &v1.ApprovalStatus{Decision: v1.ApprovalDecisionRejected, DecidedBy: "bob"}
+		_, server := newFakeServer(t, archive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.Cancel(testCtx, "mySupportArchive")
+
+		// then
+		require.ErrorIs(t, err, ErrRejected)
+		assert.EqualError(t, err, "failed to cancel supportArchive mySupportArchive: failed to wait for condition Cancelled: the supportArchive was rejected by bob")
+	})
+	t.Run("should fail if not acknowledged in time", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, archiveWithCondition(v1.ConditionNodeInfoFetched))
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+		ctx, cancel := context.WithTimeout(testCtx, 50*time.Millisecond)
+		defer cancel()
+
+		// when
+		_, err := sClient.Cancel(ctx, "mySupportArchive")
+
+		// then
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, fake.objects["mySupportArchive"].Spec.Cancel)
+	})
+}
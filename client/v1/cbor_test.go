@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientfeatures "k8s.io/client-go/features"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// cborGates enables the client-go feature gate ClientsAllowCBOR.
+type cborGates struct{}
+
+func (cborGates) Enabled(feature clientfeatures.Feature) bool {
+	return feature == clientfeatures.ClientsAllowCBOR
+}
+
+func allowCBOR(t *testing.T) {
+	previous := clientfeatures.FeatureGates()
+	clientfeatures.ReplaceFeatureGates(cborGates{})
+	t.Cleanup(func() { clientfeatures.ReplaceFeatureGates(previous) })
+}
+
+func bigSupportArchiveList(count int) *v1.SupportArchiveList {
+	list := &v1.SupportArchiveList{TypeMeta: metav1.TypeMeta{Kind: "SupportArchiveList", APIVersion: v1.GroupVersion.String()}}
+	created := metav1.NewTime(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC))
+	for i := range count {
+		list.Items = append(list.Items, v1.SupportArchive{
+			TypeMeta: metav1.TypeMeta{Kind: "SupportArchive", APIVersion: v1.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fmt.Sprintf("archive-%04d", i),
+				Namespace:         "ecosystem",
+				Labels:            map[string]string{"app": "ces"},
+				CreationTimestamp: created,
+			},
+			Spec: v1.SupportArchiveSpec{ContentTimeframe: v1.ContentTimeframe{StartTime: created, EndTime: created}},
+			Status: v1.SupportArchiveStatus{
+				DownloadPath: fmt.Sprintf("/download/archive-%04d.zip", i),
+				Conditions: []metav1.Condition{
+					{Type: v1.ConditionSupportArchiveCreated, Status: metav1.ConditionTrue, Reason: "Created", LastTransitionTime: created},
+				},
+			},
+		})
+	}
+
+	return list
+}
+
+func encode(t testing.TB, mediaType string, obj runtime.Object) []byte {
+	t.Helper()
+	clientScheme, err := privateScheme()
+	require.NoError(t, err)
+	info, ok := runtime.SerializerInfoForMediaType(clientScheme.codecs.SupportedMediaTypes(), mediaType)
+	require.True(t, ok)
+	data, err := runtime.Encode(info.Serializer, obj)
+	require.NoError(t, err)
+
+	return data
+}
+
+// newContentTypeServer answers list requests with the given media type and records the Accept headers.
+func newContentTypeServer(t *testing.T, mediaType string, list *v1.SupportArchiveList) (*httptest.Server, *[]string) {
+	var accepted []string
+	body := encode(t, mediaType, list)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		accepted = append(accepted, request.Header.Get("Accept"))
+		writer.Header().Add("content-type", mediaType)
+		_, err := writer.Write(body)
+		require.NoError(t, err)
+	}))
+
+	return server, &accepted
+}
+
+func TestWithCBOR(t *testing.T) {
+	t.Run("should decode CBOR responses", func(t *testing.T) {
+		// given
+		allowCBOR(t)
+		server, accepted := newContentTypeServer(t, runtime.ContentTypeCBOR, bigSupportArchiveList(2))
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithCBOR())
+		require.NoError(t, err)
+
+		// when
+		list, err := client.SupportArchives("ecosystem").List(testCtx, metav1.ListOptions{})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, list.Items, 2)
+		assert.Equal(t, "archive-0001", list.Items[1].Name)
+		assert.Equal(t, "/download/archive-0001.zip", list.Items[1].Status.DownloadPath)
+		assert.Equal(t, []string{cborAcceptContentTypes}, *accepted)
+	})
+	t.Run("should fall back to JSON responses", func(t *testing.T) {
+		// given
+		allowCBOR(t)
+		server, _ := newContentTypeServer(t, runtime.ContentTypeJSON, bigSupportArchiveList(2))
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithCBOR())
+		require.NoError(t, err)
+
+		// when
+		list, err := client.SupportArchives("ecosystem").List(testCtx, metav1.ListOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, list.Items, 2)
+	})
+	t.Run("should accept JSON only without the feature gate", func(t *testing.T) {
+		// given
+		server, accepted := newContentTypeServer(t, runtime.ContentTypeJSON, bigSupportArchiveList(1))
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithCBOR())
+		require.NoError(t, err)
+
+		// when
+		_, err = client.SupportArchives("ecosystem").List(testCtx, metav1.ListOptions{})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, *accepted, 1)
+		assert.NotContains(t, (*accepted)[0], "cbor")
+	})
+}
+
+// BenchmarkDecodeSupportArchiveList compares the cost of decoding big lists as JSON and CBOR. The CBOR decoder accepts
+// at most 1024 items per list, which is why bigger lists are not measured.
+func BenchmarkDecodeSupportArchiveList(b *testing.B) {
+	clientScheme, err := privateScheme()
+	require.NoError(b, err)
+
+	for _, count := range []int{100, 500, 1000} {
+		list := bigSupportArchiveList(count)
+		for _, mediaType := range []string{runtime.ContentTypeJSON, runtime.ContentTypeCBOR} {
+			data := encode(b, mediaType, list)
+			info, ok := runtime.SerializerInfoForMediaType(clientScheme.codecs.SupportedMediaTypes(), mediaType)
+			require.True(b, ok)
+			decoder := clientScheme.codecs.WithoutConversion().DecoderToVersion(info.Serializer, v1.GroupVersion)
+
+			b.Run(fmt.Sprintf("%s/%d", mediaType, count), func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(data)))
+				for b.Loop() {
+					result := &v1.SupportArchiveList{}
+					_, _, err := decoder.Decode(data, nil, result)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
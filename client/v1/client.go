@@ -1,48 +1,115 @@
 package v1
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/kubernetes/scheme"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
 	"k8s.io/client-go/rest"
 
+	"go.opentelemetry.io/otel/trace"
+
 	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
 )
 
 // client wraps the rest.Interface to use as a restClient for the component client.
 type client struct {
-	restClient rest.Interface
+	restClient     rest.Interface
+	httpClient     *http.Client
+	parameterCodec runtime.ParameterCodec
+	metrics        *clientMetrics
+	tracer         trace.Tracer
+	retryPolicy    RetryPolicy
+	reviews        authenticationv1client.SelfSubjectReviewInterface
 }
 
 // NewForConfig creates a new client for a given rest.Config.
-func NewForConfig(c *rest.Config) (SupportArchiveV1Interface, error) {
+func NewForConfig(c *rest.Config, opts ...Option) (SupportArchiveV1Interface, error) {
+	options := newOptions(opts)
 	config := *c
 	gv := schema.GroupVersion{Group: v1.GroupVersion.Group, Version: v1.GroupVersion.Version}
 	config.ContentConfig.GroupVersion = &gv
 	config.APIPath = "/apis"
 
-	s := scheme.Scheme
-	err := v1.AddToScheme(s)
+	schemeFn := privateScheme
+	if options.globalScheme {
+		schemeFn = globalScheme
+	}
+	clientScheme, err := schemeFn()
 	if err != nil {
 		return nil, err
 	}
 
-	metav1.AddToGroupVersion(s, gv)
-	config.NegotiatedSerializer = scheme.Codecs.WithoutConversion()
+	config.NegotiatedSerializer = clientScheme.codecs.WithoutConversion()
+	if options.cbor {
+		config.AcceptContentTypes = cborAcceptContentTypes
+	}
 	config.UserAgent = rest.DefaultKubernetesUserAgent()
 
+	var metrics *clientMetrics
+	if options.metricsRegisterer != nil {
+		metrics, err = newClientMetrics(options.metricsRegisterer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register client metrics: %w", err)
+		}
+		config.Wrap(metrics.wrapTransport)
+	}
+	tracer := newTracer(options.tracerProvider)
+	if options.tracerProvider != nil {
+		config.Wrap(tracingTransport(tracer))
+	}
+
+	retryPolicy := DefaultRetryPolicy()
+	if options.retryPolicy != nil {
+		retryPolicy = *options.retryPolicy
+	}
+
 	restClient, err := rest.RESTClientFor(&config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &client{restClient: restClient}, nil
+	authenticationClient, err := authenticationv1client.NewForConfig(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authentication client: %w", err)
+	}
+
+	return &client{
+		restClient:     restClient,
+		httpClient:     restClient.Client,
+		parameterCodec: clientScheme.parameterCodec,
+		metrics:        metrics,
+		tracer:         tracer,
+		retryPolicy:    retryPolicy,
+		reviews:        authenticationClient.SelfSubjectReviews(),
+	}, nil
 }
 
 // SupportArchives takes a namespace and returns a new support archive client.
+// With metav1.NamespaceAll, the client lists and watches across all namespaces, see AllNamespaces.
 func (c *client) SupportArchives(namespace string) SupportArchiveInterface {
 	return &supportArchiveClient{
-		client: c.restClient,
-		ns:     namespace,
+		client:         c.restClient,
+		httpClient:     c.httpClient,
+		parameterCodec: c.parameterCodec,
+		metrics:        c.metrics,
+		tracer:         c.tracer,
+		retryPolicy:    c.retryPolicy,
+		reviews:        c.reviews,
+		ns:             namespace,
 	}
 }
+
+// AllNamespaces returns a support archive client for listing and watching supportArchives in all namespaces.
+func (c *client) AllNamespaces() SupportArchiveListWatcher {
+	return c.SupportArchives(metav1.NamespaceAll)
+}
+
+// CurrentUser returns the name of the user the client authenticates as.
+func (c *client) CurrentUser(ctx context.Context) (string, error) {
+	return currentUser(ctx, c.reviews)
+}
@@ -1,9 +1,15 @@
 package v1
 
 import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
-	"testing"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
 )
 
 func TestNewForConfig(t *testing.T) {
@@ -18,6 +24,42 @@ func TestNewForConfig(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, clientSet)
 	})
+	t.Run("should not register types at the global scheme by default", func(t *testing.T) {
+		// when
+		_, err := NewForConfig(&rest.Config{})
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, scheme.Scheme.Recognizes(v1.GroupVersion.WithKind("SupportArchive")))
+	})
+	t.Run("should be safe to call concurrently", func(t *testing.T) {
+		// given
+		var wg sync.WaitGroup
+		errs := make([]error, 10)
+
+		// when
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, errs[i] = NewForConfig(&rest.Config{})
+			}()
+		}
+		wg.Wait()
+
+		// then
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+	})
+	t.Run("should register types at the global scheme with WithGlobalScheme", func(t *testing.T) {
+		// when
+		_, err := NewForConfig(&rest.Config{}, WithGlobalScheme())
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, scheme.Scheme.Recognizes(v1.GroupVersion.WithKind("SupportArchive")))
+	})
 }
 
 func Test_client_SupportArchives(t *testing.T) {
@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudogu/k8s-support-archive-lib/integrity"
+)
+
+const defaultDownloadAttempts = 3
+
+// ServiceReference references the service that serves support archives.
+type ServiceReference struct {
+	// Namespace of the service. Defaults to the namespace of the supportArchive.
+	Namespace string
+	// Name of the service.
+	Name string
+	// Port of the service. Can be a port name or number. Defaults to the first port of the service.
+	Port string
+}
+
+// DownloadOptions configure the download of a support archive.
+type DownloadOptions struct {
+	// Service is the service that serves the archive. It is reached through the service proxy of the API server.
+	// It is required unless the DownloadPath in the status is already an API server path (starting with /api/ or /apis/).
+	Service *ServiceReference
+	// ResumeFrom contains the already downloaded beginning of the archive.
+	// Only the remaining bytes are requested and written while ResumeFrom is still included in the verification.
+	ResumeFrom io.Reader
+	// MaxAttempts limits how often the download is attempted. An interrupted download is resumed with a ranged request.
+	// Defaults to 3.
+	MaxAttempts int
+	// SkipVerification disables the verification against size and digest in the status. It is required to download
+	// archives without a digest in the status, e.g. archives of older operators.
+	SkipVerification bool
+}
+
+// errRetryableDownload marks download failures after which the download can be resumed.
+var errRetryableDownload = errors.New("download interrupted")
+
+// Download writes the archive of the supportArchive with the given name to the writer and verifies it against size
+// and digest in the status. Without a digest in the status, it fails with integrity.ErrMissingDigest before
+// downloading anything, unless SkipVerification is set.
+func (client *supportArchiveClient) Download(ctx context.Context, name string, writer io.Writer, opts DownloadOptions) error {
+	supportArchive, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get supportArchive %s: %w", name, err)
+	}
+
+	downloadURL, err := client.resolveDownloadURL(supportArchive.Status.DownloadPath, opts.Service)
+	if err != nil {
+		return fmt.Errorf("failed to resolve download path of supportArchive %s: %w", name, err)
+	}
+	if !opts.SkipVerification && supportArchive.Status.Digest == "" {
+		return fmt.Errorf("failed to verify supportArchive %s: %w", name, integrity.ErrMissingDigest)
+	}
+
+	digester := integrity.NewDigester()
+	if opts.ResumeFrom != nil {
+		_, err = io.Copy(digester, opts.ResumeFrom)
+		if err != nil {
+			return fmt.Errorf("failed to read already downloaded part of supportArchive %s: %w", name, err)
+		}
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultDownloadAttempts
+	}
+
+	out := io.MultiWriter(writer, digester)
+	for attempt := 1; ; attempt++ {
+		err = client.downloadFrom(withAttempt(ctx, attempt), downloadURL, out, digester.Size(), supportArchive.Status.Size)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errRetryableDownload) || attempt >= maxAttempts || ctx.Err() != nil {
+			return fmt.Errorf("failed to download supportArchive %s: %w", name, err)
+		}
+	}
+
+	if opts.SkipVerification {
+		return nil
+	}
+
+	err = digester.Check(supportArchive.Status)
+	if err != nil {
+		return fmt.Errorf("failed to verify supportArchive %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (client *supportArchiveClient) downloadFrom(ctx context.Context, downloadURL *url.URL, writer io.Writer, offset int64, size int64) error {
+	if size > 0 && offset >= size {
+		return nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errRetryableDownload, err)
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	body := io.Reader(response.Body)
+	switch {
+	case response.StatusCode == http.StatusPartialContent:
+	case response.StatusCode == http.StatusOK:
+		// the server ignored the range so the already downloaded bytes are skipped
+		_, err = io.CopyN(io.Discard, body, offset)
+		if err != nil {
+			return fmt.Errorf("%w: %w", errRetryableDownload, err)
+		}
+	case response.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// everything has already been downloaded
+		return nil
+	case response.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: unexpected status code %d", errRetryableDownload, response.StatusCode)
+	default:
+		return fmt.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	_, err = io.Copy(writer, &interruptibleReader{reader: body})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// interruptibleReader marks read errors as retryable to distinguish them from errors of the writer.
+type interruptibleReader struct {
+	reader io.Reader
+}
+
+func (r *interruptibleReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return n, fmt.Errorf("%w: %w", errRetryableDownload, err)
+	}
+
+	return n, err
+}
+
+// resolveDownloadURL turns the download path into a URL reachable through the API server.
+// Paths starting with /api/ or /apis/ are used as they are. Other paths are sent through the proxy of the given service.
+// If the download path is a URL, only its path and query are used.
+func (client *supportArchiveClient) resolveDownloadURL(downloadPath string, service *ServiceReference) (*url.URL, error) {
+	if downloadPath == "" {
+		return nil, fmt.Errorf("status contains no download path yet")
+	}
+
+	parsedPath, err := url.Parse(downloadPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse download path %q: %w", downloadPath, err)
+	}
+
+	var result *url.URL
+	if strings.HasPrefix(parsedPath.Path, "/api/") || strings.HasPrefix(parsedPath.Path, "/apis/") {
+		result = client.client.Get().AbsPath(parsedPath.Path).URL()
+	} else {
+		if service == nil || service.Name == "" {
+			return nil, fmt.Errorf("a service is required to download from path %q", downloadPath)
+		}
+
+		namespace := service.Namespace
+		if namespace == "" {
+			namespace = client.ns
+		}
+		serviceName := service.Name
+		if service.Port != "" {
+			serviceName = fmt.Sprintf("%s:%s", service.Name, service.Port)
+		}
+
+		result = client.client.Get().
+			AbsPath("/api/v1/namespaces", namespace, "services", serviceName, "proxy", parsedPath.Path).
+			URL()
+	}
+	result.RawQuery = parsedPath.RawQuery
+
+	return result, nil
+}
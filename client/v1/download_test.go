@@ -0,0 +1,299 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	"github.com/cloudogu/k8s-support-archive-lib/integrity"
+)
+
+const (
+	testArchiveContent = "this is the content of the support archive"
+	testArchivePath    = "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/mySupportArchive"
+)
+
+func newDownloadTestServer(t *testing.T, status v1.SupportArchiveStatus, archiveHandler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path == testArchivePath {
+			writer.Header().Add("content-type", "application/json")
+			supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}, Status: status}
+			supportArchiveBytes, err := json.Marshal(supportArchive)
+			require.NoError(t, err)
+			_, err = writer.Write(supportArchiveBytes)
+			require.NoError(t, err)
+			return
+		}
+
+		archiveHandler(writer, request)
+	}))
+}
+
+func testArchiveStatus(t *testing.T, downloadPath string) v1.SupportArchiveStatus {
+	t.Helper()
+	digest, size, err := integrity.Digest(strings.NewReader(testArchiveContent))
+	require.NoError(t, err)
+
+	return v1.SupportArchiveStatus{DownloadPath: downloadPath, Digest: digest, Size: size}
+}
+
+func Test_supportArchiveClient_Download(t *testing.T) {
+	t.Run("should download through service proxy", func(t *testing.T) {
+		// given
+		server := newDownloadTestServer(t, testArchiveStatus(t, "/archives/test.zip?version=1"), func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, http.MethodGet, request.Method)
+			assert.Equal(t, "/api/v1/namespaces/test/services/archive-service:8080/proxy/archives/test.zip", request.URL.Path)
+			assert.Equal(t, "version=1", request.URL.RawQuery)
+			assert.Empty(t, request.Header.Get("Range"))
+
+			_, err := writer.Write([]byte(testArchiveContent))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+		buffer := &bytes.Buffer{}
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", buffer, DownloadOptions{Service: &ServiceReference{Name: "archive-service", Port: "8080"}})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testArchiveContent, buffer.String())
+	})
+	t.Run("should use API server path as it is", func(t *testing.T) {
+		// given
+		server := newDownloadTestServer(t, testArchiveStatus(t, "/api/v1/namespaces/other/services/svc/proxy/test.zip"), func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/api/v1/namespaces/other/services/svc/proxy/test.zip", request.URL.Path)
+
+			_, err := writer.Write([]byte(testArchiveContent))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+		buffer := &bytes.Buffer{}
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", buffer, DownloadOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testArchiveContent, buffer.String())
+	})
+	t.Run("should resume interrupted download with ranged request", func(t *testing.T) {
+		// given
+		half := len(testArchiveContent) / 2
+		requestCounter := 0
+		server := newDownloadTestServer(t, testArchiveStatus(t, "/test.zip"), func(writer http.ResponseWriter, request *http.Request) {
+			requestCounter++
+			if requestCounter == 1 {
+				assert.Empty(t, request.Header.Get("Range"))
+				writer.Header().Set("Content-Length", strconv.Itoa(len(testArchiveContent)))
+				_, err := writer.Write([]byte(testArchiveContent[:half]))
+				require.NoError(t, err)
+				return
+			}
+
+			assert.Equal(t, fmt.Sprintf("bytes=%d-", half), request.Header.Get("Range"))
+			writer.WriteHeader(http.StatusPartialContent)
+			_, err := writer.Write([]byte(testArchiveContent[half:]))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+		buffer := &bytes.Buffer{}
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", buffer, DownloadOptions{Service: &ServiceReference{Name: "svc"}})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, requestCounter)
+		assert.Equal(t, testArchiveContent, buffer.String())
+	})
+	t.Run("should resume from already downloaded part", func(t *testing.T) {
+		// given
+		server := newDownloadTestServer(t, testArchiveStatus(t, "/test.zip"), func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "bytes=4-", request.Header.Get("Range"))
+			writer.WriteHeader(http.StatusPartialContent)
+			_, err := writer.Write([]byte(testArchiveContent[4:]))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+		buffer := &bytes.Buffer{}
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", buffer, DownloadOptions{
+			Service:    &ServiceReference{Name: "svc"},
+			ResumeFrom: strings.NewReader(testArchiveContent[:4]),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testArchiveContent[4:], buffer.String())
+	})
+	t.Run("should skip already downloaded part if server ignores range", func(t *testing.T) {
+		// given
+		server := newDownloadTestServer(t, testArchiveStatus(t, "/test.zip"), func(writer http.ResponseWriter, request *http.Request) {
+			_, err := writer.Write([]byte(testArchiveContent))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+		buffer := &bytes.Buffer{}
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", buffer, DownloadOptions{
+			Service:    &ServiceReference{Name: "svc"},
+			ResumeFrom: strings.NewReader(testArchiveContent[:4]),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testArchiveContent[4:], buffer.String())
+	})
+	t.Run("should fail on digest mismatch", func(t *testing.T) {
+		// given
+		server := newDownloadTestServer(t, testArchiveStatus(t, "/test.zip"), func(writer http.ResponseWriter, request *http.Request) {
+			_, err := writer.Write([]byte(strings.ToUpper(testArchiveContent)))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", &bytes.Buffer{}, DownloadOptions{Service: &ServiceReference{Name: "svc"}})
+
+		// then
+		require.ErrorIs(t, err, integrity.ErrDigestMismatch)
+		assert.ErrorContains(t, err, "failed to verify supportArchive mySupportArchive")
+	})
+	t.Run("should fail without digest before downloading", func(t *testing.T) {
+		// given
+		status := testArchiveStatus(t, "/test.zip")
+		status.Digest = ""
+		requestCounter := 0
+		server := newDownloadTestServer(t, status, func(writer http.ResponseWriter, request *http.Request) {
+			requestCounter++
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", &bytes.Buffer{}, DownloadOptions{Service: &ServiceReference{Name: "svc"}})
+
+		// then
+		require.ErrorIs(t, err, integrity.ErrMissingDigest)
+		assert.ErrorContains(t, err, "failed to verify supportArchive mySupportArchive")
+		assert.Zero(t, requestCounter)
+	})
+	t.Run("should download without digest if verification is skipped", func(t *testing.T) {
+		// given
+		status := testArchiveStatus(t, "/test.zip")
+		status.Digest = ""
+		server := newDownloadTestServer(t, status, func(writer http.ResponseWriter, request *http.Request) {
+			_, err := writer.Write([]byte(testArchiveContent))
+			require.NoError(t, err)
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+		buffer := &bytes.Buffer{}
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", buffer, DownloadOptions{Service: &ServiceReference{Name: "svc"}, SkipVerification: true})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testArchiveContent, buffer.String())
+	})
+	t.Run("should not retry on client error", func(t *testing.T) {
+		// given
+		requestCounter := 0
+		server := newDownloadTestServer(t, testArchiveStatus(t, "/test.zip"), func(writer http.ResponseWriter, request *http.Request) {
+			requestCounter++
+			writer.WriteHeader(http.StatusNotFound)
+		})
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", &bytes.Buffer{}, DownloadOptions{Service: &ServiceReference{Name: "svc"}})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unexpected status code 404")
+		assert.Equal(t, 1, requestCounter)
+	})
+	t.Run("should fail without download path", func(t *testing.T) {
+		// given
+		server := newDownloadTestServer(t, v1.SupportArchiveStatus{}, nil)
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", &bytes.Buffer{}, DownloadOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "status contains no download path yet")
+	})
+	t.Run("should fail without service for non-API path", func(t *testing.T) {
+		// given
+		server := newDownloadTestServer(t, testArchiveStatus(t, "/test.zip"), nil)
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+
+		// when
+		err = sClient.Download(testCtx, "mySupportArchive", &bytes.Buffer{}, DownloadOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "a service is required to download from path \"/test.zip\"")
+	})
+}
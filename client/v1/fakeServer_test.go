@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// fakeServer serves support archives and applies JSON and merge patches to them like the API server.
+type fakeServer struct {
+	mu      sync.Mutex
+	objects map[string]*v1.SupportArchive
+	patches int
+	// onPatch is called once before the next patch is applied to simulate concurrent changes.
+	onPatch func(name string)
+	// failWith lets all patches fail with the status code. failures lets the next patches fail in order.
+	failWith int
+	failures []int
+	// user is the name returned for SelfSubjectReviews.
+	user string
+}
+
+func newFakeServer(t *testing.T, objects ...*v1.SupportArchive) (*fakeServer, *httptest.Server) {
+	fake := &fakeServer{objects: map[string]*v1.SupportArchive{}}
+	for _, object := range objects {
+		fake.objects[object.Name] = object.DeepCopy()
+		fake.objects[object.Name].ResourceVersion = "1"
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+
+		writer.Header().Add("content-type", "application/json")
+		_, name := requestObject(request)
+		switch request.Method {
+		case http.MethodGet:
+			if name == "" {
+				list := &v1.SupportArchiveList{}
+				for _, object := range fake.objects {
+					list.Items = append(list.Items, *object)
+				}
+				require.NoError(t, json.NewEncoder(writer).Encode(list))
+				return
+			}
+			require.NoError(t, json.NewEncoder(writer).Encode(fake.objects[name]))
+		case http.MethodPost:
+			require.True(t, strings.HasSuffix(request.URL.Path, "/selfsubjectreviews"), "unexpected post to %s", request.URL.Path)
+			require.NoError(t, json.NewEncoder(writer).Encode(&authenticationv1.SelfSubjectReview{
+				TypeMeta: metav1.TypeMeta{Kind: "SelfSubjectReview", APIVersion: authenticationv1.SchemeGroupVersion.String()},
+				Status:   authenticationv1.SelfSubjectReviewStatus{UserInfo: authenticationv1.UserInfo{Username: fake.user}},
+			}))
+		case http.MethodPatch:
+			fake.patches++
+			if fake.failWith != 0 {
+				writer.WriteHeader(fake.failWith)
+				return
+			}
+			if len(fake.failures) > 0 {
+				writer.WriteHeader(fake.failures[0])
+				fake.failures = fake.failures[1:]
+				return
+			}
+			if fake.onPatch != nil {
+				fake.onPatch(name)
+				fake.onPatch = nil
+				fake.touch(name)
+			}
+			fake.applyPatch(t, writer, request, name)
+		default:
+			t.Errorf("unexpected method %s", request.Method)
+		}
+	}))
+
+	return fake, server
+}
+
+// newFakeServerTestClient creates a client for the fake server that retries conflicts without delay.
+func newFakeServerTestClient(t *testing.T, server *httptest.Server) SupportArchiveInterface {
+	client, err := NewForConfig(&rest.Config{Host: server.URL}, WithRetryPolicy(testRetryPolicy(3, nil)))
+	require.NoError(t, err)
+
+	return client.SupportArchives("test")
+}
+
+func (fake *fakeServer) touch(name string) {
+	version, _ := strconv.Atoi(fake.objects[name].ResourceVersion)
+	fake.objects[name].ResourceVersion = strconv.Itoa(version + 1)
+}
+
+func (fake *fakeServer) applyPatch(t *testing.T, writer http.ResponseWriter, request *http.Request, name string) {
+	body, err := io.ReadAll(request.Body)
+	require.NoError(t, err)
+	original, err := json.Marshal(fake.objects[name])
+	require.NoError(t, err)
+
+	var patched []byte
+	switch types.PatchType(request.Header.Get("Content-Type")) {
+	case types.JSONPatchType:
+		patch, decodeErr := jsonpatch.DecodePatch(body)
+		require.NoError(t, decodeErr)
+		patched, err = patch.Apply(original)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			// the API server reports failed JSON patches like this
+			fake.writeStatus(t, writer, apierrors.NewGenericServerResponse(http.StatusUnprocessableEntity, "", schema.GroupResource{}, "", err.Error(), 0, false))
+			return
+		}
+		require.NoError(t, err)
+	case types.MergePatchType:
+		precondition := &v1.SupportArchive{}
+		require.NoError(t, json.Unmarshal(body, precondition))
+		if precondition.ResourceVersion != "" && precondition.ResourceVersion != fake.objects[name].ResourceVersion {
+			fake.writeStatus(t, writer, apierrors.NewConflict(supportArchiveResource, name, errors.New("the object has been modified")))
+			return
+		}
+		patched, err = jsonpatch.MergePatch(original, body)
+		require.NoError(t, err)
+	default:
+		t.Errorf("unexpected patch type %s", request.Header.Get("Content-Type"))
+		return
+	}
+
+	result := &v1.SupportArchive{}
+	require.NoError(t, json.Unmarshal(patched, result))
+	result.ResourceVersion = fake.objects[name].ResourceVersion
+	fake.objects[name] = result
+	fake.touch(name)
+	require.NoError(t, json.NewEncoder(writer).Encode(result))
+}
+
+func (fake *fakeServer) writeStatus(t *testing.T, writer http.ResponseWriter, err *apierrors.StatusError) {
+	status := err.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writer.WriteHeader(int(status.Code))
+	assert.NoError(t, json.NewEncoder(writer).Encode(status))
+}
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const (
+	// FieldRequester is the field selector key of the requester in the spec.
+	FieldRequester = "spec.requester"
+	// FieldTicket is the field selector key of the ticket in the spec.
+	FieldTicket = "spec.ticket"
+)
+
+// ListFilter selects supportArchives by the requester and ticket in their spec. Empty fields match every value.
+type ListFilter struct {
+	// Requester selects supportArchives requested by the user.
+	Requester string
+	// Ticket selects supportArchives created for the support case.
+	Ticket string
+}
+
+// Apply adds field selectors for the filter to the list options, keeping any existing field selector. The API server
+// supports field selectors for custom resources from Kubernetes 1.31 on. For older clusters, use Matches instead.
+func (f ListFilter) Apply(opts metav1.ListOptions) (metav1.ListOptions, error) {
+	var selectors []fields.Selector
+	if opts.FieldSelector != "" {
+		existing, err := fields.ParseSelector(opts.FieldSelector)
+		if err != nil {
+			return opts, fmt.Errorf("failed to parse field selector %q: %w", opts.FieldSelector, err)
+		}
+		selectors = append(selectors, existing)
+	}
+	if f.Requester != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector(FieldRequester, f.Requester))
+	}
+	if f.Ticket != "" {
+		selectors = append(selectors, fields.OneTermEqualSelector(FieldTicket, f.Ticket))
+	}
+	if len(selectors) > 0 {
+		opts.FieldSelector = fields.AndSelectors(selectors...).String()
+	}
+
+	return opts, nil
+}
+
+// Matches returns true if the supportArchive matches the filter.
+func (f ListFilter) Matches(supportArchive *v1.SupportArchive) bool {
+	return (f.Requester == "" || f.Requester == supportArchive.Spec.Requester) &&
+		(f.Ticket == "" || f.Ticket == supportArchive.Spec.Ticket)
+}
@@ -0,0 +1,56 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+func TestListFilter_Apply(t *testing.T) {
+	t.Run("should add field selectors", func(t *testing.T) {
+		// when
+		opts, err := ListFilter{Requester: "alice", Ticket: "SUP-1234"}.Apply(metav1.ListOptions{LabelSelector: "app=ces"})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, metav1.ListOptions{LabelSelector: "app=ces", FieldSelector: "spec.requester=alice,spec.ticket=SUP-1234"}, opts)
+	})
+	t.Run("should keep existing field selector and escape values", func(t *testing.T) {
+		// when
+		opts, err := ListFilter{Requester: "system:serviceaccount:ecosystem:a,b"}.Apply(metav1.ListOptions{FieldSelector: "metadata.name=my-archive"})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, `metadata.name=my-archive,spec.requester=system:serviceaccount:ecosystem:a\,b`, opts.FieldSelector)
+	})
+	t.Run("should not change options for empty filter", func(t *testing.T) {
+		// when
+		opts, err := ListFilter{}.Apply(metav1.ListOptions{Limit: 10})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, metav1.ListOptions{Limit: 10}, opts)
+	})
+	t.Run("should fail for invalid existing field selector", func(t *testing.T) {
+		// when
+		_, err := ListFilter{Ticket: "SUP-1"}.Apply(metav1.ListOptions{FieldSelector: "a=b=c"})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse field selector \"a=b=c\"")
+	})
+}
+
+func TestListFilter_Matches(t *testing.T) {
+	archive := &v1.SupportArchive{Spec: v1.SupportArchiveSpec{Requester: "alice", Ticket: "SUP-1234"}}
+
+	assert.True(t, ListFilter{}.Matches(archive))
+	assert.True(t, ListFilter{Requester: "alice", Ticket: "SUP-1234"}.Matches(archive))
+	assert.False(t, ListFilter{Requester: "bob"}.Matches(archive))
+	assert.False(t, ListFilter{Ticket: "SUP-1"}.Matches(archive))
+}
@@ -0,0 +1,187 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var supportArchiveResource = schema.GroupResource{Group: v1.GroupVersion.Group, Resource: "supportarchives"}
+
+// jsonPatchTestFailed is part of the message of failed test operations in JSON patches.
+const jsonPatchTestFailed = "test failed"
+
+// FinalizerCleanupOptions select the support archives and finalizers for CleanupFinalizers.
+type FinalizerCleanupOptions struct {
+	// ListOptions select the support archives to clean up.
+	ListOptions metav1.ListOptions
+	// Finalizers are removed from the support archives. If empty, all finalizers are removed.
+	Finalizers []string
+	// OnlyTerminating restricts the cleanup to support archives that are already being deleted.
+	OnlyTerminating bool
+}
+
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// AddFinalizer adds the given finalizer to the supportArchive, retrying according to the RetryPolicy of the client.
+func (client *supportArchiveClient) AddFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (result *v1.SupportArchive, err error) {
+	ctx, span := client.startSpan(ctx, "AddFinalizer", supportArchive.Name)
+	defer func() { endSpan(span, err) }()
+
+	result, err = client.patchFinalizers(ctx, supportArchive, func(finalizers []string) []string {
+		if slices.Contains(finalizers, finalizer) {
+			return finalizers
+		}
+		return append(finalizers, finalizer)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add finalizer %s to supportArchive: %w", finalizer, err)
+	}
+
+	return result, nil
+}
+
+// RemoveFinalizer removes the given finalizer to the supportArchive, retrying according to the RetryPolicy of the client.
+func (client *supportArchiveClient) RemoveFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (result *v1.SupportArchive, err error) {
+	ctx, span := client.startSpan(ctx, "RemoveFinalizer", supportArchive.Name)
+	defer func() { endSpan(span, err) }()
+
+	result, err = client.patchFinalizers(ctx, supportArchive, func(finalizers []string) []string {
+		return slices.DeleteFunc(finalizers, func(f string) bool { return f == finalizer })
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove finalizer %s from supportArchive: %w", finalizer, err)
+	}
+
+	return result, nil
+}
+
+// HasFinalizer returns true if the supportArchive with the given name currently has the finalizer.
+func (client *supportArchiveClient) HasFinalizer(ctx context.Context, name string, finalizer string) (bool, error) {
+	supportArchive, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get supportArchive %s: %w", name, err)
+	}
+
+	return slices.Contains(supportArchive.Finalizers, finalizer), nil
+}
+
+// CleanupFinalizers removes finalizers from all selected supportArchives, e.g. to release archives that are stuck
+// in deletion because their controller is gone. The supportArchives are listed page by page with ListAll. It returns
+// the names of the cleaned up supportArchives.
+func (client *supportArchiveClient) CleanupFinalizers(ctx context.Context, opts FinalizerCleanupOptions) ([]string, error) {
+	var cleaned []string
+	var errs []error
+	for supportArchive, err := range client.ListAll(ctx, opts.ListOptions) {
+		if err != nil {
+			return cleaned, errors.Join(append(errs, err)...)
+		}
+		if opts.OnlyTerminating && supportArchive.DeletionTimestamp == nil {
+			continue
+		}
+		if !hasAnyFinalizer(supportArchive.Finalizers, opts.Finalizers) {
+			continue
+		}
+
+		_, err := client.patchFinalizers(ctx, supportArchive, func(finalizers []string) []string {
+			if len(opts.Finalizers) == 0 {
+				return nil
+			}
+			return slices.DeleteFunc(finalizers, func(f string) bool { return slices.Contains(opts.Finalizers, f) })
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to clean up finalizers of supportArchive %s: %w", supportArchive.Name, err))
+			continue
+		}
+		cleaned = append(cleaned, supportArchive.Name)
+	}
+
+	return cleaned, errors.Join(errs...)
+}
+
+func hasAnyFinalizer(finalizers []string, wanted []string) bool {
+	if len(wanted) == 0 {
+		return len(finalizers) > 0
+	}
+
+	return slices.ContainsFunc(finalizers, func(f string) bool { return slices.Contains(wanted, f) })
+}
+
+// patchFinalizers replaces the finalizers of the supportArchive with the result of modifyFn by a JSON patch.
+// The patch tests that the finalizers did not change in the meantime, so concurrent changes are never dropped.
+// If they did change, the supportArchive is fetched again and the patch is retried according to the RetryPolicy.
+func (client *supportArchiveClient) patchFinalizers(ctx context.Context, supportArchive *v1.SupportArchive, modifyFn func([]string) []string) (result *v1.SupportArchive, err error) {
+	current := supportArchive.Finalizers
+	err = client.retryPolicy.run(ctx, func(ctx context.Context, attempt int) error {
+		if attempt > 1 {
+			currentObj, getErr := client.Get(ctx, supportArchive.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
+			}
+			current = currentObj.Finalizers
+		}
+
+		patch, patchErr := finalizerPatch(current, modifyFn(slices.Clone(current)))
+		if patchErr != nil {
+			return patchErr
+		}
+
+		var updateErr error
+		result, updateErr = client.Patch(ctx, supportArchive.Name, types.JSONPatchType, patch, metav1.PatchOptions{})
+		if isFailedPatchTest(updateErr) || apierrors.IsInvalid(updateErr) && client.finalizersChanged(ctx, supportArchive.Name, current) {
+			return apierrors.NewConflict(supportArchiveResource, supportArchive.Name, updateErr)
+		}
+		return updateErr
+	}, client.onRetry)
+
+	return result, err
+}
+
+func finalizerPatch(current []string, desired []string) ([]byte, error) {
+	operations := []jsonPatchOperation{
+		{Op: "test", Path: "/metadata/finalizers", Value: current},
+		{Op: "add", Path: "/metadata/finalizers", Value: desired},
+	}
+	if len(desired) == 0 {
+		operations[1] = jsonPatchOperation{Op: "remove", Path: "/metadata/finalizers"}
+		if len(current) == 0 {
+			operations = operations[:1]
+		}
+	}
+
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create finalizer patch: %w", err)
+	}
+
+	return patch, nil
+}
+
+// isFailedPatchTest returns true if the API server rejected a JSON patch because one of its test operations failed.
+// Only some servers keep the message of the JSON patch library. Other invalid requests, like rejections by validation
+// or admission, are no failed tests.
+func isFailedPatchTest(err error) bool {
+	return apierrors.IsInvalid(err) && strings.Contains(err.Error(), jsonPatchTestFailed)
+}
+
+// finalizersChanged returns true if the finalizers of the supportArchive differ from the tested ones. The API server
+// reports failed JSON patches as invalid requests, mostly without the message of the JSON patch library, so this
+// tells failed tests apart from other invalid requests.
+func (client *supportArchiveClient) finalizersChanged(ctx context.Context, name string, tested []string) bool {
+	supportArchive, err := client.Get(ctx, name, metav1.GetOptions{})
+	return err == nil && !slices.Equal(supportArchive.Finalizers, tested)
+}
@@ -0,0 +1,242 @@
+package v1
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+func Test_supportArchiveClient_AddFinalizer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.AddFinalizer(testCtx, supportArchive, "myFinalizer")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"myFinalizer"}, result.Finalizers)
+		assert.Equal(t, []string{"myFinalizer"}, fake.objects["mySupportArchive"].Finalizers)
+		assert.Empty(t, supportArchive.Finalizers)
+	})
+	t.Run("should keep finalizers added concurrently", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test", Labels: map[string]string{"a": "b"}}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		fake.onPatch = func(name string) {
+			fake.objects[name].Finalizers = []string{"other"}
+			fake.objects[name].Labels = map[string]string{"changed": "concurrently"}
+		}
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.AddFinalizer(testCtx, supportArchive, "myFinalizer")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"other", "myFinalizer"}, result.Finalizers)
+		assert.Equal(t, map[string]string{"changed": "concurrently"}, result.Labels)
+		assert.Equal(t, 2, fake.patches)
+	})
+	t.Run("should not add finalizer twice", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test", Finalizers: []string{"myFinalizer"}}}
+		_, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.AddFinalizer(testCtx, supportArchive, "myFinalizer")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"myFinalizer"}, result.Finalizers)
+	})
+	t.Run("should fail to set finalizer on client error", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		fake.failWith = http.StatusInternalServerError
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.AddFinalizer(testCtx, supportArchive, "myFinalizer")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to add finalizer myFinalizer to supportArchive:")
+		assert.Equal(t, 1, fake.patches)
+	})
+	t.Run("should not retry invalid requests", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		fake.failWith = http.StatusUnprocessableEntity
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.AddFinalizer(testCtx, supportArchive, "myFinalizer")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.Equal(t, 1, fake.patches)
+	})
+}
+
+func Test_supportArchiveClient_RemoveFinalizer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test", Finalizers: []string{"finalizer1", "finalizer2"}}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.RemoveFinalizer(testCtx, supportArchive, "finalizer1")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"finalizer2"}, result.Finalizers)
+		assert.Equal(t, []string{"finalizer2"}, fake.objects["mySupportArchive"].Finalizers)
+	})
+	t.Run("should remove last finalizer", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test", Finalizers: []string{"finalizer1"}}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.RemoveFinalizer(testCtx, supportArchive, "finalizer1")
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, result.Finalizers)
+		assert.Empty(t, fake.objects["mySupportArchive"].Finalizers)
+	})
+	t.Run("should fail to remove finalizer on client error", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test", Finalizers: []string{"finalizer1", "finalizer2"}}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		fake.failWith = http.StatusInternalServerError
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.RemoveFinalizer(testCtx, supportArchive, "finalizer2")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to remove finalizer finalizer2 from supportArchive")
+	})
+}
+
+func Test_supportArchiveClient_HasFinalizer(t *testing.T) {
+	// given
+	supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test", Finalizers: []string{"finalizer1"}}}
+	_, server := newFakeServer(t, supportArchive)
+	defer server.Close()
+	sClient := newFakeServerTestClient(t, server)
+
+	// when
+	hasFinalizer1, err1 := sClient.HasFinalizer(testCtx, "mySupportArchive", "finalizer1")
+	hasFinalizer2, err2 := sClient.HasFinalizer(testCtx, "mySupportArchive", "finalizer2")
+
+	// then
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	assert.True(t, hasFinalizer1)
+	assert.False(t, hasFinalizer2)
+}
+
+func Test_supportArchiveClient_CleanupFinalizers(t *testing.T) {
+	deleted := metav1.Now()
+	stuck := func() []*v1.SupportArchive {
+		return []*v1.SupportArchive{
+			{ObjectMeta: metav1.ObjectMeta{Name: "stuck", Namespace: "test", DeletionTimestamp: &deleted, Finalizers: []string{"operator", "other"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "alive", Namespace: "test", Finalizers: []string{"operator"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "untouched", Namespace: "test", DeletionTimestamp: &deleted, Finalizers: []string{"other"}}},
+		}
+	}
+
+	t.Run("should remove the given finalizers from terminating archives", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, stuck()...)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		cleaned, err := sClient.CleanupFinalizers(testCtx, FinalizerCleanupOptions{Finalizers: []string{"operator"}, OnlyTerminating: true})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"stuck"}, cleaned)
+		assert.Equal(t, []string{"other"}, fake.objects["stuck"].Finalizers)
+		assert.Equal(t, []string{"operator"}, fake.objects["alive"].Finalizers)
+		assert.Equal(t, []string{"other"}, fake.objects["untouched"].Finalizers)
+	})
+	t.Run("should remove all finalizers", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, stuck()...)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		cleaned, err := sClient.CleanupFinalizers(testCtx, FinalizerCleanupOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"stuck", "alive", "untouched"}, cleaned)
+		for _, object := range fake.objects {
+			assert.Empty(t, object.Finalizers)
+		}
+	})
+	t.Run("should list page by page", func(t *testing.T) {
+		// given
+		server, requests := newPagingServer(t, 2*defaultPageSize+1, nil)
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		cleaned, err := client.SupportArchives("test").CleanupFinalizers(testCtx, FinalizerCleanupOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, cleaned)
+		assert.Len(t, *requests, 3)
+		assert.Contains(t, (*requests)[0], "limit=500")
+	})
+	t.Run("should join errors", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, stuck()...)
+		defer server.Close()
+		fake.failWith = http.StatusInternalServerError
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		cleaned, err := sClient.CleanupFinalizers(testCtx, FinalizerCleanupOptions{OnlyTerminating: true})
+
+		// then
+		require.Error(t, err)
+		assert.Empty(t, cleaned)
+		assert.ErrorContains(t, err, "failed to clean up finalizers of supportArchive stuck")
+		assert.ErrorContains(t, err, "failed to clean up finalizers of supportArchive untouched")
+	})
+}
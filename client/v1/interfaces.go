@@ -2,6 +2,8 @@ package v1
 
 import (
 	"context"
+	"io"
+	"iter"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -11,32 +13,90 @@ import (
 )
 
 type SupportArchiveV1Interface interface {
+	// SupportArchives returns a client for the supportArchives in the given namespace.
 	SupportArchives(namespace string) SupportArchiveInterface
+	// AllNamespaces returns a client that lists and watches the supportArchives in all namespaces of the cluster.
+	AllNamespaces() SupportArchiveListWatcher
+	// CurrentUser returns the name of the user the client authenticates as.
+	CurrentUser(ctx context.Context) (string, error)
+	// SupportArchivePolicies returns a client for the cluster-scoped supportArchivePolicies.
+	SupportArchivePolicies() SupportArchivePolicyInterface
+}
+
+// SupportArchivePolicyInterface accesses the cluster-scoped supportArchivePolicies. Evaluate them with the policy package.
+type SupportArchivePolicyInterface interface {
+	// Get takes name of the supportArchivePolicy, and returns the corresponding supportArchivePolicy object, and an error if there is any.
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.SupportArchivePolicy, error)
+	// List takes label and field selectors, and returns the list of supportArchivePolicies that match those selectors.
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.SupportArchivePolicyList, error)
+	// Watch returns a watch.Interface that watches the requested supportArchivePolicies.
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	// Create takes the representation of a supportArchivePolicy and creates it. Returns the server's representation of the supportArchivePolicy, and an error, if there is any.
+	Create(ctx context.Context, policy *v1.SupportArchivePolicy, opts metav1.CreateOptions) (*v1.SupportArchivePolicy, error)
+	// Update takes the representation of a supportArchivePolicy and updates it. Returns the server's representation of the supportArchivePolicy, and an error, if there is any.
+	Update(ctx context.Context, policy *v1.SupportArchivePolicy, opts metav1.UpdateOptions) (*v1.SupportArchivePolicy, error)
+	// Delete takes name of the supportArchivePolicy and deletes it. Returns an error if one occurs.
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	// Patch applies the patch and returns the patched supportArchivePolicy.
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions) (*v1.SupportArchivePolicy, error)
+}
+
+// SupportArchiveListWatcher lists and watches supportArchives.
+type SupportArchiveListWatcher interface {
+	// List takes label and field selectors, and returns the list of supportArchives that match those selectors.
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.SupportArchiveList, error)
+	// ListAll returns an iterator over all supportArchives that match the list options, following continue tokens page by page.
+	ListAll(ctx context.Context, opts metav1.ListOptions) iter.Seq2[*v1.SupportArchive, error]
+	// Watch returns a watch.Interface that watches the requested supportArchives.
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	// WatchArchives returns an iterator over the changes of the supportArchives that match the list options.
+	// It watches again after timeouts and after the resource version expired.
+	WatchArchives(ctx context.Context, opts metav1.ListOptions) iter.Seq2[ArchiveEvent, error]
 }
 
 type SupportArchiveInterface interface {
+	SupportArchiveListWatcher
+
 	// Create takes the representation of a supportArchive and creates it.  Returns the server's representation of the supportArchive, and an error, if there is any.
 	Create(ctx context.Context, supportArchive *v1.SupportArchive, opts metav1.CreateOptions) (*v1.SupportArchive, error)
 	// Update takes the representation of a supportArchive and updates it. Returns the server's representation of the supportArchive, and an error, if there is any.
 	Update(ctx context.Context, supportArchive *v1.SupportArchive, opts metav1.UpdateOptions) (*v1.SupportArchive, error)
 	// UpdateStatus was generated because the type contains a Status member.
 	UpdateStatus(ctx context.Context, supportArchive *v1.SupportArchive, opts metav1.UpdateOptions) (*v1.SupportArchive, error)
-	// UpdateStatusWithRetry updates the status according to modifyStatusFn and if a conflict error occurs, the method will refetch the resource and retry the status update.
+	// UpdateStatusWithRetry updates the status according to modifyStatusFn and if a retryable error occurs, the method will refetch the resource and retry the status update.
+	// By default, only conflicts are retried. See WithRetryPolicy.
 	UpdateStatusWithRetry(ctx context.Context, cr *v1.SupportArchive, modifyStatusFn func(v1.SupportArchiveStatus) v1.SupportArchiveStatus, opts metav1.UpdateOptions) (*v1.SupportArchive, error)
+	// PatchStatusCondition sets the condition in the status without touching other conditions.
+	PatchStatusCondition(ctx context.Context, name string, condition metav1.Condition) (*v1.SupportArchive, error)
+	// PatchStatusErrors appends the error messages to the errors in the status.
+	PatchStatusErrors(ctx context.Context, name string, errorMessages ...string) (*v1.SupportArchive, error)
+	// PatchStatusDownloadPath sets the download path in the status.
+	PatchStatusDownloadPath(ctx context.Context, name string, downloadPath string) (*v1.SupportArchive, error)
 	// Delete takes name of the supportArchive and deletes it. Returns an error if one occurs.
 	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
 	// DeleteCollection deletes a collection of objects.
 	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
 	// Get takes name of the supportArchive, and returns the corresponding supportArchive object, and an error if there is any.
 	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.SupportArchive, error)
-	// List takes label and field selectors, and returns the list of supportArchives that match those selectors.
-	List(ctx context.Context, opts metav1.ListOptions) (*v1.SupportArchiveList, error)
-	// Watch returns a watch.Interface that watches the requested supportArchives.
-	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
 	// Patch applies the patch and returns the patched supportArchive.
 	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.SupportArchive, err error)
-	// AddFinalizer adds the given finalizer to the supportArchive.
+	// AddFinalizer adds the given finalizer to the supportArchive by a JSON patch. Failed patches are retried like in UpdateStatusWithRetry.
 	AddFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (*v1.SupportArchive, error)
-	// RemoveFinalizer removes the given finalizer to the supportArchive.
+	// RemoveFinalizer removes the given finalizer to the supportArchive by a JSON patch. Failed patches are retried like in UpdateStatusWithRetry.
 	RemoveFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (*v1.SupportArchive, error)
+	// HasFinalizer returns true if the supportArchive with the given name currently has the finalizer.
+	HasFinalizer(ctx context.Context, name string, finalizer string) (bool, error)
+	// CleanupFinalizers removes finalizers from all selected supportArchives and returns the names of the cleaned up supportArchives.
+	CleanupFinalizers(ctx context.Context, opts FinalizerCleanupOptions) ([]string, error)
+	// Download writes the archive of the supportArchive with the given name to the writer.
+	// The archive is obtained from the status' DownloadPath through the API server and verified against the status' digest.
+	Download(ctx context.Context, name string, writer io.Writer, opts DownloadOptions) error
+	// Approve records in the approval annotations that the current user approved the supportArchive, which requires approval.
+	// The requester of the supportArchive cannot approve it, see ErrSelfApproval.
+	Approve(ctx context.Context, name string, reason string) (*v1.SupportArchive, error)
+	// Reject records in the approval annotations that the current user rejected the supportArchive, which requires approval.
+	Reject(ctx context.Context, name string, reason string) (*v1.SupportArchive, error)
+	// Cancel sets Cancel in the spec of the supportArchive and waits until the operator acknowledged the cancellation
+	// with the Cancelled condition or phase. See ErrAlreadyCreated and ErrRejected.
+	Cancel(ctx context.Context, name string) (*v1.SupportArchive, error)
 }
@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const (
+	// defaultPageSize is used by ListAll if the list options contain no limit.
+	defaultPageSize = 500
+	// maxExpirations limits how often ListAll continues after expired continue tokens.
+	maxExpirations = 3
+)
+
+// ListAll returns an iterator over all supportArchives that match the list options. The supportArchives are requested
+// in pages of opts.Limit items, following the continue tokens, so only one page is held in memory at once.
+//
+// If a continue token expires, the list continues with the token the API server offers for an inconsistent
+// continuation or, if there is none, lists again from the start and skips the already returned supportArchives.
+// In both cases, the list is no longer a consistent snapshot.
+//
+// The iteration stops after the first error, which is returned together with a nil supportArchive.
+func (client *supportArchiveClient) ListAll(ctx context.Context, opts metav1.ListOptions) iter.Seq2[*v1.SupportArchive, error] {
+	return func(yield func(*v1.SupportArchive, error) bool) {
+		if opts.Limit <= 0 {
+			opts.Limit = defaultPageSize
+		}
+
+		var last *v1.SupportArchive
+		relisted := false
+		expirations := 0
+		for {
+			list, err := client.List(ctx, opts)
+			if isExpired(err) && opts.Continue != "" && expirations < maxExpirations {
+				expirations++
+				opts.Continue = inconsistentContinue(err)
+				relisted = opts.Continue == ""
+				continue
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to list supportArchives: %w", err))
+				return
+			}
+
+			for i := range list.Items {
+				item := &list.Items[i]
+				if relisted && last != nil && !listedAfter(item, last) {
+					continue
+				}
+				relisted = false
+				last = item
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if list.Continue == "" {
+				return
+			}
+			opts.Continue = list.Continue
+		}
+	}
+}
+
+func isExpired(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+// inconsistentContinue returns the continue token the API server offers with an expired continue token.
+func inconsistentContinue(err error) string {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return ""
+	}
+
+	return status.Status().ListMeta.Continue
+}
+
+// listedAfter returns true if the API server lists item after last. Lists are ordered by their storage key, which
+// ends with namespace/name, so namespaces like "a-b" are listed before "a".
+func listedAfter(item *v1.SupportArchive, last *v1.SupportArchive) bool {
+	return item.Namespace+"/"+item.Name > last.Namespace+"/"+last.Name
+}
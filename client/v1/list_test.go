@@ -0,0 +1,253 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// newPagingServer serves the given number of supportArchives in pages. Continue tokens are the index of the next item.
+// expire is called for every continue token and lets it expire with the returned status if it is not nil.
+func newPagingServer(t *testing.T, count int, expire func(token string) *metav1.Status) (*httptest.Server, *[]string) {
+	var items []v1.SupportArchive
+	for i := range count {
+		items = append(items, v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("archive-%03d", i), Namespace: "test"}})
+	}
+
+	return newPagingServerFor(t, items, expire)
+}
+
+// newPagingServerFor serves the given supportArchives in pages like newPagingServer.
+func newPagingServerFor(t *testing.T, items []v1.SupportArchive, expire func(token string) *metav1.Status) (*httptest.Server, *[]string) {
+	count := len(items)
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		token := request.URL.Query().Get("continue")
+		requests = append(requests, request.URL.RawQuery)
+		writer.Header().Add("content-type", "application/json")
+
+		if token != "" && expire != nil {
+			if status := expire(token); status != nil {
+				writer.WriteHeader(int(status.Code))
+				require.NoError(t, json.NewEncoder(writer).Encode(status))
+				return
+			}
+		}
+
+		start, _ := strconv.Atoi(token)
+		limit, err := strconv.Atoi(request.URL.Query().Get("limit"))
+		require.NoError(t, err)
+
+		list := &v1.SupportArchiveList{}
+		for i := start; i < count && i < start+limit; i++ {
+			list.Items = append(list.Items, items[i])
+		}
+		if start+limit < count {
+			list.Continue = strconv.Itoa(start + limit)
+		}
+		require.NoError(t, json.NewEncoder(writer).Encode(list))
+	}))
+
+	return server, &requests
+}
+
+func expiredStatus(inconsistentContinue string) *metav1.Status {
+	return &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		ListMeta: metav1.ListMeta{Continue: inconsistentContinue},
+		Status:   metav1.StatusFailure,
+		Reason:   metav1.StatusReasonExpired,
+		Code:     http.StatusGone,
+		Message:  "The provided continue parameter is too old to display a consistent list result.",
+	}
+}
+
+func collectNames(t *testing.T, sClient SupportArchiveInterface, opts metav1.ListOptions) ([]string, error) {
+	t.Helper()
+	var names []string
+	for supportArchive, err := range sClient.ListAll(testCtx, opts) {
+		if err != nil {
+			return names, err
+		}
+		names = append(names, supportArchive.Name)
+	}
+
+	return names, nil
+}
+
+func archiveNames(from int, to int) []string {
+	var names []string
+	for i := from; i < to; i++ {
+		names = append(names, fmt.Sprintf("archive-%03d", i))
+	}
+
+	return names
+}
+
+func Test_supportArchiveClient_ListAll(t *testing.T) {
+	t.Run("should follow continue tokens", func(t *testing.T) {
+		// given
+		server, requests := newPagingServer(t, 7, nil)
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		names, err := collectNames(t, client.SupportArchives("test"), metav1.ListOptions{Limit: 3, LabelSelector: "app=ces"})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, archiveNames(0, 7), names)
+		assert.Equal(t, []string{"labelSelector=app%3Dces&limit=3", "continue=3&labelSelector=app%3Dces&limit=3", "continue=6&labelSelector=app%3Dces&limit=3"}, *requests)
+	})
+	t.Run("should use default page size", func(t *testing.T) {
+		// given
+		server, requests := newPagingServer(t, 2, nil)
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		names, err := collectNames(t, client.SupportArchives("test"), metav1.ListOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, archiveNames(0, 2), names)
+		assert.Equal(t, []string{"limit=500"}, *requests)
+	})
+	t.Run("should stop when the caller stops", func(t *testing.T) {
+		// given
+		server, requests := newPagingServer(t, 7, nil)
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		count := 0
+		for range client.SupportArchives("test").ListAll(testCtx, metav1.ListOptions{Limit: 3}) {
+			count++
+			if count == 2 {
+				break
+			}
+		}
+
+		// then
+		assert.Len(t, *requests, 1)
+	})
+	t.Run("should continue inconsistently after expired token", func(t *testing.T) {
+		// given
+		expired := false
+		server, requests := newPagingServer(t, 7, func(token string) *metav1.Status {
+			if token == "3" && !expired {
+				expired = true
+				return expiredStatus("3")
+			}
+			return nil
+		})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		names, err := collectNames(t, client.SupportArchives("test"), metav1.ListOptions{Limit: 3})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, archiveNames(0, 7), names)
+		assert.Len(t, *requests, 4)
+	})
+	t.Run("should relist and skip returned items after expired token", func(t *testing.T) {
+		// given
+		expired := false
+		server, _ := newPagingServer(t, 7, func(token string) *metav1.Status {
+			if token == "6" && !expired {
+				expired = true
+				return expiredStatus("")
+			}
+			return nil
+		})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		names, err := collectNames(t, client.SupportArchives("test"), metav1.ListOptions{Limit: 3})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, archiveNames(0, 7), names)
+	})
+	t.Run("should relist and skip returned items in the order of the storage keys", func(t *testing.T) {
+		// given
+		var items []v1.SupportArchive
+		for _, key := range []string{"a-b/x", "a-b/y", "a/x", "a/y"} {
+			namespace, name, _ := strings.Cut(key, "/")
+			items = append(items, v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}})
+		}
+		expired := false
+		server, _ := newPagingServerFor(t, items, func(token string) *metav1.Status {
+			if token == "2" && !expired {
+				expired = true
+				return expiredStatus("")
+			}
+			return nil
+		})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		var keys []string
+		for supportArchive, err := range client.AllNamespaces().ListAll(testCtx, metav1.ListOptions{Limit: 2}) {
+			require.NoError(t, err)
+			keys = append(keys, supportArchive.Namespace+"/"+supportArchive.Name)
+		}
+
+		// then
+		assert.Equal(t, []string{"a-b/x", "a-b/y", "a/x", "a/y"}, keys)
+	})
+	t.Run("should fail on error", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		names, err := collectNames(t, client.SupportArchives("test"), metav1.ListOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to list supportArchives")
+		assert.Empty(t, names)
+	})
+	t.Run("should fail if the first page is expired", func(t *testing.T) {
+		// given
+		server, _ := newPagingServer(t, 7, func(string) *metav1.Status {
+			return expiredStatus("")
+		})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		_, err = collectNames(t, client.SupportArchives("test"), metav1.ListOptions{Limit: 3, Continue: "3"})
+
+		// then
+		require.Error(t, err)
+	})
+}
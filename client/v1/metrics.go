@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "support_archive_client"
+
+// clientMetrics holds the Prometheus metrics of the client. A nil *clientMetrics records nothing.
+type clientMetrics struct {
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	conflictRetries prometheus.Counter
+	watchReconnects prometheus.Counter
+}
+
+func newClientMetrics(registerer prometheus.Registerer) (*clientMetrics, error) {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "requests_total",
+		Help:      "Number of requests to the API server by verb and HTTP status code.",
+	}, []string{"verb", "code"})
+	requestDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of requests to the API server by verb until the response headers are received.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"verb"})
+	conflictRetries := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "conflict_retries_total",
+		Help:      "Number of requests retried because of a conflict.",
+	})
+	watchReconnects := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "watch_reconnects_total",
+		Help:      "Number of watches started again by WatchArchives after the previous watch of the same iteration ended.",
+	})
+
+	var err error
+	metrics := &clientMetrics{}
+	if metrics.requests, err = register(registerer, requests); err != nil {
+		return nil, err
+	}
+	if metrics.requestDuration, err = register(registerer, requestDuration); err != nil {
+		return nil, err
+	}
+	if metrics.conflictRetries, err = register(registerer, conflictRetries); err != nil {
+		return nil, err
+	}
+	if metrics.watchReconnects, err = register(registerer, watchReconnects); err != nil {
+		return nil, err
+	}
+
+	return metrics, nil
+}
+
+// register registers the collector or returns the already registered equal collector.
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	err := registerer.Register(collector)
+	if err == nil {
+		return collector, nil
+	}
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+			return existing, nil
+		}
+	}
+
+	return collector, err
+}
+
+func (m *clientMetrics) conflictRetry() {
+	if m == nil {
+		return
+	}
+	m.conflictRetries.Inc()
+}
+
+func (m *clientMetrics) watchReconnect() {
+	if m == nil {
+		return
+	}
+	m.watchReconnects.Inc()
+}
+
+// wrapTransport instruments all requests of the given round tripper.
+func (m *clientMetrics) wrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &instrumentedRoundTripper{metrics: m, delegate: rt}
+}
+
+type instrumentedRoundTripper struct {
+	metrics  *clientMetrics
+	delegate http.RoundTripper
+}
+
+func (rt *instrumentedRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	verb := requestVerb(request)
+	start := time.Now()
+	response, err := rt.delegate.RoundTrip(request)
+	rt.metrics.requestDuration.WithLabelValues(verb).Observe(time.Since(start).Seconds())
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(response.StatusCode)
+	}
+	rt.metrics.requests.WithLabelValues(verb, code).Inc()
+
+	return response, err
+}
+
+// requestVerb derives the Kubernetes verb from the HTTP method and path of the request.
+func requestVerb(request *http.Request) string {
+	path := strings.TrimSuffix(request.URL.Path, "/")
+	if strings.Contains(path, "/proxy/") {
+		return "download"
+	}
+	collection := strings.HasSuffix(strings.ToLower(path), "/supportarchives")
+
+	switch request.Method {
+	case http.MethodGet:
+		if request.URL.Query().Get("watch") == "true" {
+			return "watch"
+		}
+		if collection {
+			return "list"
+		}
+		return "get"
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut:
+		return "update"
+	case http.MethodPatch:
+		return "patch"
+	case http.MethodDelete:
+		if collection {
+			return "deletecollection"
+		}
+		return "delete"
+	default:
+		return strings.ToLower(request.Method)
+	}
+}
@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+func TestWithMetrics(t *testing.T) {
+	t.Run("should count requests by verb and code", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Method == http.MethodDelete {
+				writer.WriteHeader(http.StatusNotFound)
+				return
+			}
+			writer.Header().Add("content-type", "application/json")
+			supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "testsupportArchive", Namespace: "test"}}
+			supportArchiveBytes, err := json.Marshal(supportArchive)
+			require.NoError(t, err)
+			_, err = writer.Write(supportArchiveBytes)
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		registry := prometheus.NewRegistry()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithMetrics(registry))
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+
+		// when
+		_, err = sClient.Get(testCtx, "testsupportArchive", metav1.GetOptions{})
+		require.NoError(t, err)
+		_, err = sClient.Get(testCtx, "testsupportArchive", metav1.GetOptions{})
+		require.NoError(t, err)
+		err = sClient.Delete(testCtx, "testsupportArchive", metav1.DeleteOptions{})
+
+		// then
+		require.Error(t, err)
+		metrics, err := newClientMetrics(registry)
+		require.NoError(t, err)
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.requests.WithLabelValues("get", "200")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues("delete", "404")))
+		assert.Equal(t, 2, testutil.CollectAndCount(metrics.requestDuration))
+	})
+	t.Run("should count conflict retries", func(t *testing.T) {
+		// given
+		putRequestCounter := 0
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Method == http.MethodPut && putRequestCounter == 0 {
+				putRequestCounter++
+				writer.Header().Add("content-type", "application/json")
+				writer.WriteHeader(http.StatusConflict)
+				_, err := writer.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Conflict","code":409}`))
+				require.NoError(t, err)
+				return
+			}
+			writer.Header().Add("content-type", "application/json")
+			supportArchiveBytes, err := json.Marshal(&v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+			require.NoError(t, err)
+			_, err = writer.Write(supportArchiveBytes)
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		registry := prometheus.NewRegistry()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithMetrics(registry))
+		require.NoError(t, err)
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+
+		// when
+		_, err = client.SupportArchives("test").UpdateStatusWithRetry(testCtx, supportArchive, func(status v1.SupportArchiveStatus) v1.SupportArchiveStatus {
+			return status
+		}, metav1.UpdateOptions{})
+
+		// then
+		require.NoError(t, err)
+		metrics, err := newClientMetrics(registry)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.conflictRetries))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues("update", "409")))
+	})
+	t.Run("should count conflict retries of finalizer patches", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		fake.onPatch = func(name string) {
+			fake.objects[name].Finalizers = append(fake.objects[name].Finalizers, "other")
+		}
+
+		registry := prometheus.NewRegistry()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithMetrics(registry), WithRetryPolicy(testRetryPolicy(3, nil)))
+		require.NoError(t, err)
+
+		// when
+		_, err = client.SupportArchives("test").AddFinalizer(testCtx, supportArchive, "myFinalizer")
+
+		// then
+		require.NoError(t, err)
+		metrics, err := newClientMetrics(registry)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.conflictRetries))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues("patch", "422")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.requests.WithLabelValues("patch", "200")))
+	})
+	t.Run("should not count watches as reconnects", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Add("content-type", "application/json")
+			writer.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		registry := prometheus.NewRegistry()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithMetrics(registry))
+		require.NoError(t, err)
+		sClient := client.SupportArchives("test")
+
+		// when
+		first, err := sClient.Watch(testCtx, metav1.ListOptions{})
+		require.NoError(t, err)
+		first.Stop()
+		second, err := sClient.Watch(testCtx, metav1.ListOptions{ResourceVersion: "42"})
+		require.NoError(t, err)
+		second.Stop()
+
+		// then
+		metrics, err := newClientMetrics(registry)
+		require.NoError(t, err)
+		assert.Zero(t, testutil.ToFloat64(metrics.watchReconnects))
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.requests.WithLabelValues("watch", "200")))
+	})
+	t.Run("should share metrics between clients with the same registerer", func(t *testing.T) {
+		// given
+		registry := prometheus.NewRegistry()
+		_, err := NewForConfig(&rest.Config{}, WithMetrics(registry))
+		require.NoError(t, err)
+
+		// when
+		_, err = NewForConfig(&rest.Config{}, WithMetrics(registry))
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_requestVerb(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		want   string
+	}{
+		{"get", http.MethodGet, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/a", "get"},
+		{"list", http.MethodGet, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives", "list"},
+		{"watch", http.MethodGet, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives?watch=true", "watch"},
+		{"create", http.MethodPost, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives", "create"},
+		{"update", http.MethodPut, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/a/status", "update"},
+		{"patch", http.MethodPatch, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/a", "patch"},
+		{"delete", http.MethodDelete, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/a", "delete"},
+		{"deletecollection", http.MethodDelete, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives", "deletecollection"},
+		{"download", http.MethodGet, "/api/v1/namespaces/test/services/archives:8080/proxy/a.zip", "download"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL, err := url.Parse(tt.url)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, requestVerb(&http.Request{Method: tt.method, URL: requestURL}))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// multiNamespaceClient lists and watches the supportArchives of a fixed set of namespaces.
+type multiNamespaceClient struct {
+	namespaces []string
+	clients    []SupportArchiveListWatcher
+}
+
+// NewMultiNamespaceClient returns a client that lists and watches the supportArchives in the given namespaces and
+// merges the results. It only needs permissions in these namespaces, unlike AllNamespaces.
+//
+// As the results of several requests are merged, lists contain neither a resource version nor a continue token.
+// Limit and Continue of the list options are therefore not supported by List; use ListAll to list page by page.
+func NewMultiNamespaceClient(client SupportArchiveV1Interface, namespaces ...string) SupportArchiveListWatcher {
+	namespaces = slices.Clone(namespaces)
+	slices.Sort(namespaces)
+	namespaces = slices.Compact(namespaces)
+
+	multiClient := &multiNamespaceClient{namespaces: namespaces}
+	for _, namespace := range namespaces {
+		multiClient.clients = append(multiClient.clients, client.SupportArchives(namespace))
+	}
+
+	return multiClient
+}
+
+// List returns the supportArchives of all namespaces sorted by namespace and name. Every namespace is listed page by
+// page with ListAll.
+func (client *multiNamespaceClient) List(ctx context.Context, opts metav1.ListOptions) (*v1.SupportArchiveList, error) {
+	if opts.Limit > 0 || opts.Continue != "" {
+		return nil, errors.New("limit and continue are not supported when listing several namespaces")
+	}
+
+	result := &v1.SupportArchiveList{}
+	for i, namespaceClient := range client.clients {
+		for supportArchive, err := range namespaceClient.ListAll(ctx, opts) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to list namespace %s: %w", client.namespaces[i], err)
+			}
+			result.Items = append(result.Items, *supportArchive)
+		}
+	}
+
+	return result, nil
+}
+
+// ListAll returns an iterator over the supportArchives of all namespaces, one namespace after another.
+func (client *multiNamespaceClient) ListAll(ctx context.Context, opts metav1.ListOptions) iter.Seq2[*v1.SupportArchive, error] {
+	return func(yield func(*v1.SupportArchive, error) bool) {
+		for _, namespaceClient := range client.clients {
+			for supportArchive, err := range namespaceClient.ListAll(ctx, opts) {
+				if !yield(supportArchive, err) || err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Watch watches the supportArchives of all namespaces and merges their events into one result channel.
+// Resource versions are valid across namespaces, so opts.ResourceVersion may be taken from any of them.
+func (client *multiNamespaceClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var watchers []watch.Interface
+	for i, namespaceClient := range client.clients {
+		watcher, err := namespaceClient.Watch(ctx, opts)
+		if err != nil {
+			for _, started := range watchers {
+				started.Stop()
+			}
+			return nil, fmt.Errorf("failed to watch supportArchives in namespace %s: %w", client.namespaces[i], err)
+		}
+		watchers = append(watchers, watcher)
+	}
+
+	return newMergedWatcher(watchers), nil
+}
+
+// WatchArchives returns an iterator over the changes of the supportArchives in all namespaces. Every namespace is
+// watched on its own as described in SupportArchiveInterface.WatchArchives. The iteration ends after the first error
+// in any namespace.
+func (client *multiNamespaceClient) WatchArchives(ctx context.Context, opts metav1.ListOptions) iter.Seq2[ArchiveEvent, error] {
+	return func(yield func(ArchiveEvent, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		type result struct {
+			event ArchiveEvent
+			err   error
+		}
+		results := make(chan result)
+		var wg sync.WaitGroup
+		for i, namespaceClient := range client.clients {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for event, err := range namespaceClient.WatchArchives(ctx, opts) {
+					if err != nil {
+						err = fmt.Errorf("namespace %s: %w", client.namespaces[i], err)
+					}
+					select {
+					case results <- result{event: event, err: err}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for r := range results {
+			if !yield(r.event, r.err) || r.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// mergedWatcher forwards the events of several watchers to one result channel. If one of the watchers ends, all are
+// stopped and the result channel is closed, so that callers notice the end and watch again instead of silently
+// missing the events of a namespace.
+type mergedWatcher struct {
+	watchers []watch.Interface
+	result   chan watch.Event
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newMergedWatcher(watchers []watch.Interface) *mergedWatcher {
+	merged := &mergedWatcher{watchers: watchers, result: make(chan watch.Event), done: make(chan struct{})}
+
+	var wg sync.WaitGroup
+	for _, watcher := range watchers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer merged.Stop()
+			for event := range watcher.ResultChan() {
+				// Stopped watchers may still send errors, e.g. about their cancelled requests. They must not be
+				// forwarded, as select picks randomly if the result is read after Stop.
+				select {
+				case <-merged.done:
+					return
+				default:
+				}
+				select {
+				case merged.result <- event:
+				case <-merged.done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(merged.result)
+	}()
+
+	return merged
+}
+
+// Stop stops all watchers. The result channel is closed once all of them are stopped.
+func (merged *mergedWatcher) Stop() {
+	merged.stopOnce.Do(func() {
+		close(merged.done)
+		for _, watcher := range merged.watchers {
+			watcher.Stop()
+		}
+	})
+}
+
+// ResultChan returns the merged events of all watchers.
+func (merged *mergedWatcher) ResultChan() <-chan watch.Event {
+	return merged.result
+}
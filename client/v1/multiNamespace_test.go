@@ -0,0 +1,331 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const supportArchivesPath = "/apis/k8s.cloudogu.com/v1/supportarchives"
+
+func namespacedArchive(namespace string, name string) *v1.SupportArchive {
+	return &v1.SupportArchive{
+		TypeMeta:   metav1.TypeMeta{Kind: "SupportArchive", APIVersion: v1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, ResourceVersion: "1"},
+	}
+}
+
+// newNamespacesServer serves the supportArchives of every namespace as list and, for watch requests, as added events.
+// Namespaces without entry fail with 403 Forbidden. It records the requested paths.
+func newNamespacesServer(t *testing.T, archives map[string][]string) (*httptest.Server, func() []string) {
+	var mutex sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		mutex.Lock()
+		paths = append(paths, request.URL.Path)
+		mutex.Unlock()
+		writer.Header().Add("content-type", "application/json")
+
+		namespace := strings.TrimPrefix(strings.TrimSuffix(request.URL.Path, "/supportarchives"), "/apis/k8s.cloudogu.com/v1/namespaces/")
+		if request.URL.Path == supportArchivesPath {
+			namespace = ""
+		}
+		names, ok := archives[namespace]
+		if !ok {
+			writer.WriteHeader(http.StatusForbidden)
+			require.NoError(t, json.NewEncoder(writer).Encode(&metav1.Status{
+				TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+				Status:   metav1.StatusFailure,
+				Reason:   metav1.StatusReasonForbidden,
+				Code:     http.StatusForbidden,
+				Message:  "access denied",
+			}))
+			return
+		}
+
+		encoder := json.NewEncoder(writer)
+		if request.URL.Query().Get("watch") != "true" {
+			list := &v1.SupportArchiveList{}
+			for _, name := range names {
+				list.Items = append(list.Items, *namespacedArchive(namespace, name))
+			}
+			require.NoError(t, encoder.Encode(list))
+			return
+		}
+
+		for _, name := range names {
+			require.NoError(t, encoder.Encode(testWatchEvent{Type: watch.Added, Object: namespacedArchive(namespace, name)}))
+		}
+		writer.(http.Flusher).Flush()
+		<-request.Context().Done()
+	}))
+
+	return server, func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string(nil), paths...)
+	}
+}
+
+func Test_client_AllNamespaces(t *testing.T) {
+	t.Run("should list without namespace", func(t *testing.T) {
+		// given
+		server, paths := newNamespacesServer(t, map[string][]string{"": {"a"}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		list, err := client.AllNamespaces().List(testCtx, metav1.ListOptions{})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, []string{supportArchivesPath}, paths())
+	})
+	t.Run("should watch without namespace", func(t *testing.T) {
+		// given
+		server, paths := newNamespacesServer(t, map[string][]string{"": {"a"}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		watcher, err := client.AllNamespaces().Watch(testCtx, metav1.ListOptions{})
+		require.NoError(t, err)
+		event := <-watcher.ResultChan()
+		watcher.Stop()
+
+		// then
+		assert.Equal(t, watch.Added, event.Type)
+		assert.Equal(t, []string{supportArchivesPath}, paths())
+	})
+}
+
+func Test_multiNamespaceClient_List(t *testing.T) {
+	t.Run("should merge the lists of all namespaces", func(t *testing.T) {
+		// given
+		server, paths := newNamespacesServer(t, map[string][]string{"ecosystem": {"a", "b"}, "monitoring": {"c"}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sut := NewMultiNamespaceClient(client, "monitoring", "ecosystem", "monitoring")
+
+		// when
+		list, err := sut.List(testCtx, metav1.ListOptions{})
+
+		// then
+		require.NoError(t, err)
+		var names []string
+		for _, item := range list.Items {
+			names = append(names, item.Namespace+"/"+item.Name)
+		}
+		assert.Equal(t, []string{"ecosystem/a", "ecosystem/b", "monitoring/c"}, names)
+		assert.Len(t, paths(), 2)
+	})
+	t.Run("should fail if a namespace fails", func(t *testing.T) {
+		// given
+		server, _ := newNamespacesServer(t, map[string][]string{"ecosystem": {"a"}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sut := NewMultiNamespaceClient(client, "ecosystem", "monitoring")
+
+		// when
+		_, err = sut.List(testCtx, metav1.ListOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to list namespace monitoring: failed to list supportArchives: access denied")
+	})
+	t.Run("should list every namespace page by page", func(t *testing.T) {
+		// given
+		server, requests := newPagingServer(t, 2*defaultPageSize+1, nil)
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sut := NewMultiNamespaceClient(client, "test")
+
+		// when
+		list, err := sut.List(testCtx, metav1.ListOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, list.Items, 2*defaultPageSize+1)
+		assert.Len(t, *requests, 3)
+	})
+	t.Run("should fail with limit", func(t *testing.T) {
+		// given
+		sut := NewMultiNamespaceClient(&client{}, "ecosystem", "monitoring")
+
+		// when
+		_, err := sut.List(testCtx, metav1.ListOptions{Limit: 10})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "limit and continue are not supported")
+	})
+}
+
+func Test_multiNamespaceClient_ListAll(t *testing.T) {
+	t.Run("should iterate over all namespaces", func(t *testing.T) {
+		// given
+		server, _ := newNamespacesServer(t, map[string][]string{"ecosystem": {"a"}, "monitoring": {"b", "c"}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sut := NewMultiNamespaceClient(client, "ecosystem", "monitoring")
+
+		// when
+		var names []string
+		for supportArchive, err := range sut.ListAll(testCtx, metav1.ListOptions{}) {
+			require.NoError(t, err)
+			names = append(names, supportArchive.Name)
+		}
+
+		// then
+		assert.Equal(t, []string{"a", "b", "c"}, names)
+	})
+}
+
+func Test_multiNamespaceClient_Watch(t *testing.T) {
+	t.Run("should merge the events of all namespaces", func(t *testing.T) {
+		// given
+		server, _ := newNamespacesServer(t, map[string][]string{"ecosystem": {"a"}, "monitoring": {"b"}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sut := NewMultiNamespaceClient(client, "ecosystem", "monitoring")
+
+		// when
+		watcher, err := sut.Watch(testCtx, metav1.ListOptions{})
+		require.NoError(t, err)
+		var names []string
+		for event := range watcher.ResultChan() {
+			names = append(names, event.Object.(*v1.SupportArchive).Name)
+			if len(names) == 2 {
+				watcher.Stop()
+			}
+		}
+
+		// then
+		assert.ElementsMatch(t, []string{"a", "b"}, names)
+	})
+	t.Run("should fail if a namespace fails", func(t *testing.T) {
+		// given
+		server, _ := newNamespacesServer(t, map[string][]string{"ecosystem": {"a"}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sut := NewMultiNamespaceClient(client, "ecosystem", "monitoring")
+
+		// when
+		_, err = sut.Watch(testCtx, metav1.ListOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to watch supportArchives in namespace monitoring")
+	})
+}
+
+func Test_mergedWatcher(t *testing.T) {
+	t.Run("should close result if stopped during a send", func(t *testing.T) {
+		// given
+		first, second := watch.NewFake(), watch.NewFake()
+		sut := newMergedWatcher([]watch.Interface{first, second})
+		// returns once the event is received, so that it is sent to the unread result
+		first.Add(namespacedArchive("ecosystem", "a"))
+
+		// when
+		sut.Stop()
+
+		// then
+		assertClosedEventually(t, sut.ResultChan())
+		assert.True(t, first.IsStopped())
+		assert.True(t, second.IsStopped())
+	})
+	t.Run("should stop all watchers and close result if one watcher ends", func(t *testing.T) {
+		// given
+		first, second := watch.NewFake(), watch.NewFake()
+		sut := newMergedWatcher([]watch.Interface{first, second})
+
+		// when
+		first.Stop()
+
+		// then
+		assertClosedEventually(t, sut.ResultChan())
+		assert.True(t, second.IsStopped())
+	})
+}
+
+// assertClosedEventually reads the channel until it is closed and fails if that takes longer than a second.
+func assertClosedEventually(t *testing.T, result <-chan watch.Event) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-result:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			require.Fail(t, "result channel was not closed")
+			return
+		}
+	}
+}
+
+func Test_multiNamespaceClient_WatchArchives(t *testing.T) {
+	t.Run("should merge the events of all namespaces", func(t *testing.T) {
+		// given
+		server, _ := newNamespacesServer(t, map[string][]string{"ecosystem": {"a"}, "monitoring": {"b"}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sut := NewMultiNamespaceClient(client, "ecosystem", "monitoring")
+
+		// when
+		var names []string
+		for event, err := range sut.WatchArchives(testCtx, metav1.ListOptions{}) {
+			require.NoError(t, err)
+			names = append(names, event.SupportArchive.Namespace+"/"+event.SupportArchive.Name)
+			if len(names) == 2 {
+				break
+			}
+		}
+
+		// then
+		assert.ElementsMatch(t, []string{"ecosystem/a", "monitoring/b"}, names)
+	})
+	t.Run("should end after the first error", func(t *testing.T) {
+		// given
+		server, _ := newNamespacesServer(t, map[string][]string{"ecosystem": {}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		sut := NewMultiNamespaceClient(client, "ecosystem", "monitoring")
+
+		// when
+		var errs []error
+		for _, err := range sut.WatchArchives(testCtx, metav1.ListOptions{}) {
+			errs = append(errs, err)
+		}
+
+		// then
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "namespace monitoring: failed to watch supportArchives: access denied")
+	})
+}
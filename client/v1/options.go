@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures the client created by NewForConfig.
+type Option func(*options)
+
+type options struct {
+	metricsRegisterer prometheus.Registerer
+	tracerProvider    trace.TracerProvider
+	retryPolicy       *RetryPolicy
+	globalScheme      bool
+	cbor              bool
+}
+
+// WithMetrics instruments the client with Prometheus metrics about requests, conflict retries and watch reconnects.
+// The metrics are registered at the given registerer. Clients using the same registerer share their metrics.
+func WithMetrics(registerer prometheus.Registerer) Option {
+	return func(opts *options) {
+		opts.metricsRegisterer = registerer
+	}
+}
+
+// WithTracing wraps the requests of the client in OpenTelemetry spans created by the given provider.
+// The spans carry the namespace, name, verb and retry attempt. Operations consisting of several requests, like
+// UpdateStatusWithRetry, get an additional parent span.
+func WithTracing(provider trace.TracerProvider) Option {
+	return func(opts *options) {
+		opts.tracerProvider = provider
+	}
+}
+
+func newOptions(opts []Option) *options {
+	result := &options{}
+	for _, opt := range opts {
+		opt(result)
+	}
+
+	return result
+}
@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// policyClient accesses the cluster-scoped supportArchivePolicies.
+type policyClient struct {
+	client         rest.Interface
+	parameterCodec runtime.ParameterCodec
+}
+
+// SupportArchivePolicies returns a client for the cluster-scoped supportArchivePolicies.
+func (c *client) SupportArchivePolicies() SupportArchivePolicyInterface {
+	return &policyClient{
+		client:         c.restClient,
+		parameterCodec: c.parameterCodec,
+	}
+}
+
+// Get takes name of the supportArchivePolicy, and returns the corresponding supportArchivePolicy object, and an error if there is any.
+func (client *policyClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (result *v1.SupportArchivePolicy, err error) {
+	result = &v1.SupportArchivePolicy{}
+	err = client.client.Get().
+		Resource("supportArchivePolicies").
+		Name(name).
+		VersionedParams(&opts, client.parameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of supportArchivePolicies that match those selectors.
+func (client *policyClient) List(ctx context.Context, opts metav1.ListOptions) (result *v1.SupportArchivePolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.SupportArchivePolicyList{}
+	err = client.client.Get().
+		Resource("supportArchivePolicies").
+		VersionedParams(&opts, client.parameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested supportArchivePolicies.
+func (client *policyClient) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return client.client.Get().
+		Resource("supportArchivePolicies").
+		VersionedParams(&opts, client.parameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a supportArchivePolicy and creates it. Returns the server's representation of the supportArchivePolicy, and an error, if there is any.
+func (client *policyClient) Create(ctx context.Context, policy *v1.SupportArchivePolicy, opts metav1.CreateOptions) (result *v1.SupportArchivePolicy, err error) {
+	result = &v1.SupportArchivePolicy{}
+	err = client.client.Post().
+		Resource("supportArchivePolicies").
+		VersionedParams(&opts, client.parameterCodec).
+		Body(policy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a supportArchivePolicy and updates it. Returns the server's representation of the supportArchivePolicy, and an error, if there is any.
+func (client *policyClient) Update(ctx context.Context, policy *v1.SupportArchivePolicy, opts metav1.UpdateOptions) (result *v1.SupportArchivePolicy, err error) {
+	result = &v1.SupportArchivePolicy{}
+	err = client.client.Put().
+		Resource("supportArchivePolicies").
+		Name(policy.Name).
+		VersionedParams(&opts, client.parameterCodec).
+		Body(policy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the supportArchivePolicy and deletes it. Returns an error if one occurs.
+func (client *policyClient) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return client.client.Delete().
+		Resource("supportArchivePolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched supportArchivePolicy.
+func (client *policyClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions) (result *v1.SupportArchivePolicy, err error) {
+	result = &v1.SupportArchivePolicy{}
+	err = client.client.Patch(pt).
+		Resource("supportArchivePolicies").
+		Name(name).
+		VersionedParams(&opts, client.parameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,158 @@
+package v1
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const policiesPath = "/apis/k8s.cloudogu.com/v1/supportarchivepolicies"
+
+// newPolicyServer asserts the method and path of the request and answers with the request body or the response.
+func newPolicyServer(t *testing.T, method string, path string, response any) SupportArchivePolicyInterface {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, method, request.Method)
+		assert.Equal(t, path, request.URL.Path)
+
+		writer.Header().Add("content-type", "application/json")
+		if response == nil {
+			body, err := io.ReadAll(request.Body)
+			require.NoError(t, err)
+			_, err = writer.Write(body)
+			require.NoError(t, err)
+			return
+		}
+		require.NoError(t, json.NewEncoder(writer).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+	return client.SupportArchivePolicies()
+}
+
+func testPolicy() *v1.SupportArchivePolicy {
+	return &v1.SupportArchivePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "no-secrets"},
+		Spec:       v1.SupportArchivePolicySpec{ForbiddenContents: []v1.ContentCategory{v1.ContentSensitiveData}},
+	}
+}
+
+func Test_policyClient_Get(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		sut := newPolicyServer(t, http.MethodGet, policiesPath+"/no-secrets", testPolicy())
+
+		// when
+		policy, err := sut.Get(testCtx, "no-secrets", metav1.GetOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testPolicy().Spec, policy.Spec)
+	})
+}
+
+func Test_policyClient_List(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		sut := newPolicyServer(t, http.MethodGet, policiesPath, v1.SupportArchivePolicyList{Items: []v1.SupportArchivePolicy{*testPolicy()}})
+		timeout := int64(5)
+
+		// when
+		list, err := sut.List(testCtx, metav1.ListOptions{TimeoutSeconds: &timeout})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "no-secrets", list.Items[0].Name)
+	})
+}
+
+func Test_policyClient_Watch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, policiesPath, request.URL.Path)
+			assert.Equal(t, "labelSelector=test&timeout=5s&timeoutSeconds=5&watch=true", request.URL.RawQuery)
+
+			writer.Header().Add("content-type", "application/json")
+		}))
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		timeout := int64(5)
+
+		// when
+		_, err = client.SupportArchivePolicies().Watch(testCtx, metav1.ListOptions{LabelSelector: "test", TimeoutSeconds: &timeout})
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_policyClient_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		sut := newPolicyServer(t, http.MethodPost, policiesPath, nil)
+
+		// when
+		policy, err := sut.Create(testCtx, testPolicy(), metav1.CreateOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testPolicy().Spec, policy.Spec)
+	})
+}
+
+func Test_policyClient_Update(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		sut := newPolicyServer(t, http.MethodPut, policiesPath+"/no-secrets", nil)
+
+		// when
+		policy, err := sut.Update(testCtx, testPolicy(), metav1.UpdateOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "no-secrets", policy.Name)
+	})
+}
+
+func Test_policyClient_Delete(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		sut := newPolicyServer(t, http.MethodDelete, policiesPath+"/no-secrets", metav1.Status{Status: metav1.StatusSuccess})
+
+		// when
+		err := sut.Delete(testCtx, "no-secrets", metav1.DeleteOptions{})
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_policyClient_Patch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		sut := newPolicyServer(t, http.MethodPatch, policiesPath+"/no-secrets", testPolicy())
+
+		// when
+		policy, err := sut.Patch(testCtx, "no-secrets", types.MergePatchType, []byte(`{"spec":{"forbiddenContents":["SensitiveData"]}}`), metav1.PatchOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testPolicy().Spec, policy.Spec)
+	})
+}
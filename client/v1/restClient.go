@@ -2,72 +2,62 @@ package v1
 
 import (
 	"context"
-	"fmt"
+	"net/http"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
-	"k8s.io/client-go/kubernetes/scheme"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
 	"k8s.io/client-go/rest"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
-	"github.com/cloudogu/retry-lib/retry"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type supportArchiveClient struct {
-	client rest.Interface
-	ns     string
+	client         rest.Interface
+	httpClient     *http.Client
+	parameterCodec runtime.ParameterCodec
+	metrics        *clientMetrics
+	tracer         trace.Tracer
+	retryPolicy    RetryPolicy
+	reviews        authenticationv1client.SelfSubjectReviewInterface
+	ns             string
 }
 
-// UpdateStatusWithRetry updates the status of the resource, retrying if a conflict error arises.
+// UpdateStatusWithRetry updates the status of the resource, retrying according to the RetryPolicy of the client.
 func (client *supportArchiveClient) UpdateStatusWithRetry(ctx context.Context, cr *v1.SupportArchive, modifyStatusFn func(v1.SupportArchiveStatus) v1.SupportArchiveStatus, opts metav1.UpdateOptions) (result *v1.SupportArchive, err error) {
-	firstTry := true
-
-	var currentObj *v1.SupportArchive
-	err = retry.OnConflict(func() error {
-		if firstTry {
-			firstTry = false
-			currentObj = cr.DeepCopy()
-		} else {
-			currentObj, err = client.Get(ctx, cr.Name, metav1.GetOptions{})
-			if err != nil {
-				return err
+	ctx, span := client.startSpan(ctx, "UpdateStatusWithRetry", cr.Name)
+	defer func() { endSpan(span, err) }()
+
+	err = client.retryPolicy.run(ctx, func(ctx context.Context, attempt int) error {
+		currentObj := cr.DeepCopy()
+		if attempt > 1 {
+			var getErr error
+			currentObj, getErr = client.Get(ctx, cr.Name, metav1.GetOptions{})
+			if getErr != nil {
+				return getErr
 			}
 		}
 
 		currentObj.Status = modifyStatusFn(currentObj.Status)
-		currentObj, err = client.UpdateStatus(ctx, currentObj, opts)
-		return err
-	})
+		var updateErr error
+		result, updateErr = client.UpdateStatus(ctx, currentObj, opts)
+		return updateErr
+	}, client.onRetry)
 	if err != nil {
 		return nil, err
 	}
 
-	return currentObj, nil
-}
-
-// AddFinalizer adds the given finalizer to the supportArchive.
-func (client *supportArchiveClient) AddFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (*v1.SupportArchive, error) {
-	controllerutil.AddFinalizer(supportArchive, finalizer)
-	result, err := client.Update(ctx, supportArchive, metav1.UpdateOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to add finalizer %s to supportArchive: %w", finalizer, err)
-	}
-
 	return result, nil
 }
 
-// RemoveFinalizer removes the given finalizer to the supportArchive.
-func (client *supportArchiveClient) RemoveFinalizer(ctx context.Context, supportArchive *v1.SupportArchive, finalizer string) (*v1.SupportArchive, error) {
-	controllerutil.RemoveFinalizer(supportArchive, finalizer)
-	result, err := client.Update(ctx, supportArchive, metav1.UpdateOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to remove finalizer %s from supportArchive: %w", finalizer, err)
+func (client *supportArchiveClient) onRetry(err error) {
+	if IsConflict(err) {
+		client.metrics.conflictRetry()
 	}
-
-	return result, err
 }
 
 // Get takes name of the supportArchive, and returns the corresponding supportArchive object, and an error if there is any.
@@ -77,7 +67,7 @@ func (client *supportArchiveClient) Get(ctx context.Context, name string, option
 		Namespace(client.ns).
 		Resource("supportArchives").
 		Name(name).
-		VersionedParams(&options, scheme.ParameterCodec).
+		VersionedParams(&options, client.parameterCodec).
 		Do(ctx).
 		Into(result)
 	return
@@ -93,7 +83,7 @@ func (client *supportArchiveClient) List(ctx context.Context, opts metav1.ListOp
 	err = client.client.Get().
 		Namespace(client.ns).
 		Resource("supportArchives").
-		VersionedParams(&opts, scheme.ParameterCodec).
+		VersionedParams(&opts, client.parameterCodec).
 		Timeout(timeout).
 		Do(ctx).
 		Into(result)
@@ -110,7 +100,7 @@ func (client *supportArchiveClient) Watch(ctx context.Context, opts metav1.ListO
 	return client.client.Get().
 		Namespace(client.ns).
 		Resource("supportArchives").
-		VersionedParams(&opts, scheme.ParameterCodec).
+		VersionedParams(&opts, client.parameterCodec).
 		Timeout(timeout).
 		Watch(ctx)
 }
@@ -121,7 +111,7 @@ func (client *supportArchiveClient) Create(ctx context.Context, supportArchive *
 	err = client.client.Post().
 		Namespace(client.ns).
 		Resource("supportArchives").
-		VersionedParams(&opts, scheme.ParameterCodec).
+		VersionedParams(&opts, client.parameterCodec).
 		Body(supportArchive).
 		Do(ctx).
 		Into(result)
@@ -135,7 +125,7 @@ func (client *supportArchiveClient) Update(ctx context.Context, supportArchive *
 		Namespace(client.ns).
 		Resource("supportArchives").
 		Name(supportArchive.Name).
-		VersionedParams(&opts, scheme.ParameterCodec).
+		VersionedParams(&opts, client.parameterCodec).
 		Body(supportArchive).
 		Do(ctx).
 		Into(result)
@@ -151,7 +141,7 @@ func (client *supportArchiveClient) UpdateStatus(ctx context.Context, supportArc
 		Resource("supportArchives").
 		Name(supportArchive.Name).
 		SubResource("status").
-		VersionedParams(&opts, scheme.ParameterCodec).
+		VersionedParams(&opts, client.parameterCodec).
 		Body(supportArchive).
 		Do(ctx).
 		Into(result)
@@ -178,7 +168,7 @@ func (client *supportArchiveClient) DeleteCollection(ctx context.Context, opts m
 	return client.client.Delete().
 		Namespace(client.ns).
 		Resource("supportArchives").
-		VersionedParams(&listOpts, scheme.ParameterCodec).
+		VersionedParams(&listOpts, client.parameterCodec).
 		Timeout(timeout).
 		Body(&opts).
 		Do(ctx).
@@ -193,7 +183,7 @@ func (client *supportArchiveClient) Patch(ctx context.Context, name string, pt t
 		Resource("supportArchives").
 		Name(name).
 		SubResource(subresources...).
-		VersionedParams(&opts, scheme.ParameterCodec).
+		VersionedParams(&opts, client.parameterCodec).
 		Body(data).
 		Do(ctx).
 		Into(result)
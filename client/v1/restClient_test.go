@@ -17,7 +17,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
 )
@@ -317,162 +316,6 @@ func Test_supportArchiveClient_Watch(t *testing.T) {
 	})
 }
 
-func Test_supportArchiveClient_AddFinalizer(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		// given
-		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
-
-		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-			assert.Equal(t, http.MethodPut, request.Method)
-			assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/mySupportArchive", request.URL.Path)
-
-			bytes, err := io.ReadAll(request.Body)
-			require.NoError(t, err)
-
-			createdSupportArchive := &v1.SupportArchive{}
-			require.NoError(t, json.Unmarshal(bytes, createdSupportArchive))
-			assert.Equal(t, "mySupportArchive", createdSupportArchive.Name)
-			assert.Len(t, createdSupportArchive.Finalizers, 1)
-			assert.Equal(t, "myFinalizer", createdSupportArchive.Finalizers[0])
-
-			writer.Header().Add("content-type", "application/json")
-			_, err = writer.Write(bytes)
-			require.NoError(t, err)
-		}))
-
-		config := rest.Config{
-			Host: server.URL,
-		}
-		client, err := NewForConfig(&config)
-		require.NoError(t, err)
-		sClient := client.SupportArchives("test")
-
-		// when
-		_, err = sClient.AddFinalizer(testCtx, supportArchive, "myFinalizer")
-
-		// then
-		require.NoError(t, err)
-	})
-
-	t.Run("should fail to set finalizer on client error", func(t *testing.T) {
-		// given
-		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
-
-		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-			assert.Equal(t, http.MethodPut, request.Method)
-			assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/mySupportArchive", request.URL.Path)
-
-			bytes, err := io.ReadAll(request.Body)
-			require.NoError(t, err)
-
-			createdSupportArchive := &v1.SupportArchive{}
-			require.NoError(t, json.Unmarshal(bytes, createdSupportArchive))
-			assert.Equal(t, "mySupportArchive", createdSupportArchive.Name)
-			assert.Len(t, createdSupportArchive.Finalizers, 1)
-			assert.Equal(t, "myFinalizer", createdSupportArchive.Finalizers[0])
-
-			writer.WriteHeader(500)
-			writer.Header().Add("content-type", "application/json")
-			_, err = writer.Write(bytes)
-			require.NoError(t, err)
-		}))
-
-		config := rest.Config{
-			Host: server.URL,
-		}
-		client, err := NewForConfig(&config)
-		require.NoError(t, err)
-		sClient := client.SupportArchives("test")
-
-		// when
-		_, err = sClient.AddFinalizer(testCtx, supportArchive, "myFinalizer")
-
-		// then
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "failed to add finalizer myFinalizer to supportArchive:")
-	})
-}
-
-func Test_supportArchiveClient_RemoveFinalizer(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		// given
-		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
-		controllerutil.AddFinalizer(supportArchive, "finalizer1")
-		controllerutil.AddFinalizer(supportArchive, "finalizer2")
-
-		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-			assert.Equal(t, http.MethodPut, request.Method)
-			assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/mySupportArchive", request.URL.Path)
-
-			bytes, err := io.ReadAll(request.Body)
-			require.NoError(t, err)
-
-			createdSupportArchive := &v1.SupportArchive{}
-			require.NoError(t, json.Unmarshal(bytes, createdSupportArchive))
-			assert.Equal(t, "mySupportArchive", createdSupportArchive.Name)
-			assert.Len(t, createdSupportArchive.Finalizers, 1)
-			assert.Equal(t, "finalizer2", createdSupportArchive.Finalizers[0])
-
-			writer.Header().Add("content-type", "application/json")
-			_, err = writer.Write(bytes)
-			require.NoError(t, err)
-		}))
-
-		config := rest.Config{
-			Host: server.URL,
-		}
-		client, err := NewForConfig(&config)
-		require.NoError(t, err)
-		sClient := client.SupportArchives("test")
-
-		// when
-		_, err = sClient.RemoveFinalizer(testCtx, supportArchive, "finalizer1")
-
-		// then
-		require.NoError(t, err)
-	})
-
-	t.Run("should fail to set finalizer on client error", func(t *testing.T) {
-		// given
-		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
-		controllerutil.AddFinalizer(supportArchive, "finalizer1")
-		controllerutil.AddFinalizer(supportArchive, "finalizer2")
-
-		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
-			assert.Equal(t, http.MethodPut, request.Method)
-			assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/mySupportArchive", request.URL.Path)
-
-			bytes, err := io.ReadAll(request.Body)
-			require.NoError(t, err)
-
-			createdSupportArchive := &v1.SupportArchive{}
-			require.NoError(t, json.Unmarshal(bytes, createdSupportArchive))
-			assert.Equal(t, "mySupportArchive", createdSupportArchive.Name)
-			assert.Len(t, createdSupportArchive.Finalizers, 1)
-			assert.Equal(t, "finalizer1", createdSupportArchive.Finalizers[0])
-
-			writer.WriteHeader(500)
-			writer.Header().Add("content-type", "application/json")
-			_, err = writer.Write(bytes)
-			require.NoError(t, err)
-		}))
-
-		config := rest.Config{
-			Host: server.URL,
-		}
-		client, err := NewForConfig(&config)
-		require.NoError(t, err)
-		sClient := client.SupportArchives("test")
-
-		// when
-		_, err = sClient.RemoveFinalizer(testCtx, supportArchive, "finalizer2")
-
-		// then
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "failed to remove finalizer finalizer2 from supportArchive")
-	})
-}
-
 func Test_supportArchiveClient_UpdateStatusWithRetry(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		// given
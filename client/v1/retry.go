@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// RetryPolicy controls how UpdateStatusWithRetry, AddFinalizer and RemoveFinalizer retry failed updates.
+// Before every retry the support archive is fetched again so that the update is applied to its current state.
+type RetryPolicy struct {
+	// Backoff determines the waiting time between two attempts. Its Steps limit how often the waiting time increases.
+	Backoff wait.Backoff
+	// MaxAttempts limits the number of attempts including the first one. Values below 1 allow unlimited attempts.
+	MaxAttempts int
+	// Retryable decides if an attempt failing with the given error is retried. Defaults to IsConflict.
+	Retryable func(error) bool
+	// Timeout limits the time spent for all attempts. The deadline of the context is respected in any case.
+	Timeout time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used if no other policy is configured. Like retry.OnConflict from the
+// retry-lib, it retries conflicts only and gives up after 9 attempts, which takes about 75 seconds.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Backoff: wait.Backoff{
+			Duration: 1500 * time.Millisecond,
+			Factor:   1.5,
+			Steps:    9,
+			Cap:      30 * time.Second,
+		},
+		MaxAttempts: 9,
+		Retryable:   IsConflict,
+	}
+}
+
+// IsConflict returns true if the error is a conflict.
+func IsConflict(err error) bool {
+	return apierrors.IsConflict(err)
+}
+
+// IsTransient returns true if the error is a conflict or indicates a stressed API server, like timeouts, throttling
+// or an unavailable service.
+func IsTransient(err error) bool {
+	return apierrors.IsConflict(err) ||
+		apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsUnexpectedServerError(err)
+}
+
+// WithRetryPolicy replaces the DefaultRetryPolicy of the client.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(opts *options) {
+		opts.retryPolicy = &policy
+	}
+}
+
+// Do calls fn until it succeeds, fails with an error that is not retryable, or the policy is exhausted. It lets other
+// clients, like the controller-runtime helpers in ctrlclient, retry exactly like this client. The attempt passed to fn
+// starts with 1.
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context, attempt int) error) error {
+	return p.run(ctx, fn, func(error) {})
+}
+
+// run calls fn until it succeeds, fails with an error that is not retryable, or the policy is exhausted.
+// The attempt passed to fn starts with 1.
+func (p RetryPolicy) run(ctx context.Context, fn func(ctx context.Context, attempt int) error, onRetry func(err error)) error {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsConflict
+	}
+
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := fn(withAttempt(ctx, attempt), attempt)
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return fmt.Errorf("the maximum number of %d attempts was reached: %w", p.MaxAttempts, err)
+		}
+
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("stopped retrying after %d attempts: %w: %w", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+		onRetry(err)
+	}
+}
@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var testResource = schema.GroupResource{Group: "k8s.cloudogu.com", Resource: "supportarchives"}
+
+func testRetryPolicy(maxAttempts int, retryable func(error) bool) RetryPolicy {
+	return RetryPolicy{
+		Backoff:     wait.Backoff{Duration: time.Millisecond},
+		MaxAttempts: maxAttempts,
+		Retryable:   retryable,
+	}
+}
+
+func TestRetryPolicy_run(t *testing.T) {
+	t.Run("should retry until success", func(t *testing.T) {
+		// given
+		policy := testRetryPolicy(3, IsConflict)
+		var attempts []int
+
+		// when
+		err := policy.run(testCtx, func(ctx context.Context, attempt int) error {
+			attempts = append(attempts, attempt)
+			assert.Equal(t, attempt, attemptFrom(ctx))
+			if attempt < 3 {
+				return apierrors.NewConflict(testResource, "a", assert.AnError)
+			}
+			return nil
+		}, func(error) {})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, attempts)
+	})
+	t.Run("should not retry errors that are not retryable", func(t *testing.T) {
+		// given
+		policy := testRetryPolicy(3, IsConflict)
+		calls := 0
+
+		// when
+		err := policy.run(testCtx, func(context.Context, int) error {
+			calls++
+			return apierrors.NewTooManyRequests("slow down", 1)
+		}, func(error) {})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, apierrors.IsTooManyRequests(err))
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should fail after max attempts", func(t *testing.T) {
+		// given
+		policy := testRetryPolicy(2, IsTransient)
+		retries := 0
+
+		// when
+		err := policy.run(testCtx, func(context.Context, int) error {
+			return apierrors.NewServerTimeout(testResource, "update", 1)
+		}, func(error) { retries++ })
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "the maximum number of 2 attempts was reached")
+		assert.True(t, apierrors.IsServerTimeout(err))
+		assert.Equal(t, 1, retries)
+	})
+	t.Run("should stop at timeout", func(t *testing.T) {
+		// given
+		policy := RetryPolicy{Backoff: wait.Backoff{Duration: time.Hour}, Timeout: 10 * time.Millisecond}
+
+		// when
+		err := policy.run(testCtx, func(context.Context, int) error {
+			return apierrors.NewConflict(testResource, "a", assert.AnError)
+		}, func(error) {})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, apierrors.IsConflict(err))
+	})
+}
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	t.Run("should give up after a bounded number of conflicts", func(t *testing.T) {
+		// given
+		policy := DefaultRetryPolicy()
+		backoff := policy.Backoff
+		var waited time.Duration
+		for range policy.MaxAttempts - 1 {
+			waited += backoff.Step()
+		}
+		policy.Backoff = wait.Backoff{Duration: time.Millisecond}
+		calls := 0
+
+		// when
+		err := policy.run(testCtx, func(context.Context, int) error {
+			calls++
+			return apierrors.NewConflict(testResource, "a", assert.AnError)
+		}, func(error) {})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "the maximum number of 9 attempts was reached")
+		assert.True(t, apierrors.IsConflict(err))
+		assert.Equal(t, 9, calls)
+		assert.Less(t, waited, 2*time.Minute)
+	})
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.True(t, IsTransient(apierrors.NewConflict(testResource, "a", assert.AnError)))
+	assert.True(t, IsTransient(apierrors.NewTooManyRequests("slow down", 1)))
+	assert.True(t, IsTransient(apierrors.NewServiceUnavailable("unavailable")))
+	assert.True(t, IsTransient(apierrors.NewTimeoutError("timeout", 1)))
+	assert.False(t, IsTransient(apierrors.NewNotFound(testResource, "a")))
+	assert.False(t, IsTransient(assert.AnError))
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	t.Run("should retry throttled finalizer updates on the current state", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		fake.objects["mySupportArchive"].Finalizers = []string{"other"}
+		fake.failures = []int{http.StatusTooManyRequests}
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithRetryPolicy(testRetryPolicy(3, IsTransient)))
+		require.NoError(t, err)
+
+		// when
+		result, err := client.SupportArchives("test").AddFinalizer(testCtx, supportArchive, "myFinalizer")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"other", "myFinalizer"}, result.Finalizers)
+		assert.Equal(t, 2, fake.patches)
+	})
+	t.Run("should give up status update after max attempts", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Add("content-type", "application/json")
+			if request.Method == http.MethodGet {
+				_, err := writer.Write([]byte(`{"metadata":{"name":"mySupportArchive","namespace":"test"}}`))
+				require.NoError(t, err)
+				return
+			}
+			writer.WriteHeader(http.StatusConflict)
+			_, err := writer.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Conflict","code":409}`))
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithRetryPolicy(testRetryPolicy(2, nil)))
+		require.NoError(t, err)
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+
+		// when
+		_, err = client.SupportArchives("test").UpdateStatusWithRetry(testCtx, supportArchive, func(status v1.SupportArchiveStatus) v1.SupportArchiveStatus {
+			return status
+		}, metav1.UpdateOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "the maximum number of 2 attempts was reached")
+		assert.True(t, apierrors.IsConflict(err))
+	})
+}
@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"fmt"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/runtime/serializer/cbor"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// cborAcceptContentTypes prefers CBOR responses and lets servers without CBOR support answer with JSON.
+const cborAcceptContentTypes = runtime.ContentTypeCBOR + ", " + runtime.ContentTypeJSON + ";q=0.9"
+
+// clientScheme contains the codecs the client encodes and decodes supportArchives and request parameters with.
+// The codecs support CBOR in addition to JSON, see WithCBOR.
+type clientScheme struct {
+	codecs         serializer.CodecFactory
+	parameterCodec runtime.ParameterCodec
+}
+
+// privateScheme returns the scheme used by default. It is created once and never modified afterward, so that clients
+// can be created and used concurrently without touching process-wide state.
+var privateScheme = sync.OnceValues(func() (*clientScheme, error) {
+	s := runtime.NewScheme()
+	err := addToScheme(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientScheme{codecs: newCodecFactory(s), parameterCodec: runtime.NewParameterCodec(s)}, nil
+})
+
+// globalScheme registers the supportArchive types at the client-go scheme, see WithGlobalScheme. It runs only once,
+// because adding types writes to the scheme while watches of existing clients may still decode with it.
+var globalScheme = sync.OnceValues(func() (*clientScheme, error) {
+	err := addToScheme(scheme.Scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	return &clientScheme{codecs: newCodecFactory(scheme.Scheme), parameterCodec: scheme.ParameterCodec}, nil
+})
+
+func newCodecFactory(s *runtime.Scheme) serializer.CodecFactory {
+	return serializer.NewCodecFactory(s, serializer.WithSerializer(cbor.NewSerializerInfo))
+}
+
+func addToScheme(s *runtime.Scheme) error {
+	err := v1.AddToScheme(s)
+	if err != nil {
+		return fmt.Errorf("failed to add supportArchive types to scheme: %w", err)
+	}
+	metav1.AddToGroupVersion(s, v1.GroupVersion)
+
+	return nil
+}
+
+// WithGlobalScheme registers the supportArchive types at the global scheme of client-go and encodes and decodes with
+// it, like versions before the private scheme did. Use it only if other code relies on finding the types there.
+func WithGlobalScheme() Option {
+	return func(opts *options) {
+		opts.globalScheme = true
+	}
+}
+
+// WithCBOR lets the client prefer CBOR over JSON for responses, which makes decoding large lists and long-running
+// watches cheaper. Servers without CBOR support answer with JSON instead. Request bodies are still sent as JSON.
+//
+// client-go only negotiates CBOR if its feature gate ClientsAllowCBOR is enabled, e.g. with the environment variable
+// KUBE_FEATURE_ClientsAllowCBOR=true. Otherwise, the client keeps using JSON. Protobuf is not offered, because the
+// API server does not serve custom resources as protobuf.
+//
+// The CBOR decoder of apimachinery rejects arrays with more than 1024 elements. Lists that may contain more
+// supportArchives have to be requested in pages, e.g. with ListAll, whose default page size is below this limit.
+func WithCBOR() Option {
+	return func(opts *options) {
+		opts.cbor = true
+	}
+}
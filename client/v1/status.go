@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// archivePatch is a patch for a supportArchive or its status subresource.
+type archivePatch struct {
+	patchType types.PatchType
+	data      any
+}
+
+// PatchStatusCondition sets the condition in the status of the supportArchive with the given name without touching
+// other conditions. Like meta.SetStatusCondition, the LastTransitionTime only changes if the status of the condition
+// changes.
+//
+// Custom resources do not support strategic merge patches. Instead, the condition is merged by its merge key type on
+// the client and sent as JSON patch that tests that the conditions did not change in the meantime. If they did, the
+// patch is retried according to the RetryPolicy of the client.
+func (client *supportArchiveClient) PatchStatusCondition(ctx context.Context, name string, condition metav1.Condition) (result *v1.SupportArchive, err error) {
+	ctx, span := client.startSpan(ctx, "PatchStatusCondition", name)
+	defer func() { endSpan(span, err) }()
+
+	result, err = client.patchStatusWithRetry(ctx, name, func(current *v1.SupportArchive) (archivePatch, error) {
+		return conditionPatch(current, condition), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch condition %s of supportArchive %s: %w", condition.Type, name, err)
+	}
+
+	return result, nil
+}
+
+// PatchStatusErrors appends the error messages to the errors in the status of the supportArchive with the given name.
+// Errors added concurrently are kept.
+func (client *supportArchiveClient) PatchStatusErrors(ctx context.Context, name string, errorMessages ...string) (result *v1.SupportArchive, err error) {
+	ctx, span := client.startSpan(ctx, "PatchStatusErrors", name)
+	defer func() { endSpan(span, err) }()
+
+	if len(errorMessages) == 0 {
+		return client.Get(ctx, name, metav1.GetOptions{})
+	}
+
+	result, err = client.patchStatusWithRetry(ctx, name, func(current *v1.SupportArchive) (archivePatch, error) {
+		return errorsPatch(current, errorMessages), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to append errors to supportArchive %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// PatchStatusDownloadPath sets the download path in the status of the supportArchive with the given name by a merge patch.
+func (client *supportArchiveClient) PatchStatusDownloadPath(ctx context.Context, name string, downloadPath string) (result *v1.SupportArchive, err error) {
+	ctx, span := client.startSpan(ctx, "PatchStatusDownloadPath", name)
+	defer func() { endSpan(span, err) }()
+
+	patch, err := json.Marshal(map[string]any{"status": map[string]any{"downloadPath": downloadPath}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status patch: %w", err)
+	}
+
+	result, err = client.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "status")
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch download path of supportArchive %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// patchStatusWithRetry fetches the current supportArchive and patches its status with the patch created by patchFn.
+// Failed patches are retried with the then current supportArchive according to the RetryPolicy of the client.
+// If patchFn returns an error, nothing is patched and the error is returned.
+func (client *supportArchiveClient) patchStatusWithRetry(ctx context.Context, name string, patchFn func(current *v1.SupportArchive) (archivePatch, error)) (result *v1.SupportArchive, err error) {
+	return client.patchWithRetry(ctx, name, patchFn, "status")
+}
+
+// patchWithRetry is like patchStatusWithRetry, but patches the given subresources or the supportArchive itself.
+func (client *supportArchiveClient) patchWithRetry(ctx context.Context, name string, patchFn func(current *v1.SupportArchive) (archivePatch, error), subresources ...string) (result *v1.SupportArchive, err error) {
+	err = client.retryPolicy.run(ctx, func(ctx context.Context, attempt int) error {
+		current, getErr := client.Get(ctx, name, metav1.GetOptions{})
+		if getErr != nil {
+			return getErr
+		}
+
+		patch, patchFnErr := patchFn(current)
+		if patchFnErr != nil {
+			return patchFnErr
+		}
+		data, marshalErr := json.Marshal(patch.data)
+		if marshalErr != nil {
+			return fmt.Errorf("failed to create patch: %w", marshalErr)
+		}
+
+		var patchErr error
+		result, patchErr = client.Patch(ctx, name, patch.patchType, data, metav1.PatchOptions{}, subresources...)
+		if isFailedPatchTest(patchErr) {
+			return apierrors.NewConflict(supportArchiveResource, name, patchErr)
+		}
+		return patchErr
+	}, client.onRetry)
+
+	return result, err
+}
+
+// conditionPatch creates a patch that sets the condition in the status of the current supportArchive.
+func conditionPatch(current *v1.SupportArchive, condition metav1.Condition) archivePatch {
+	conditions := current.Status.DeepCopy().Conditions
+	meta.SetStatusCondition(&conditions, condition)
+	updated := meta.FindStatusCondition(conditions, condition.Type)
+
+	if len(current.Status.Conditions) == 0 {
+		return firstListEntryPatch(current, "conditions", []metav1.Condition{*updated})
+	}
+
+	for i, existing := range current.Status.Conditions {
+		if existing.Type == condition.Type {
+			path := fmt.Sprintf("/status/conditions/%d", i)
+			return archivePatch{patchType: types.JSONPatchType, data: []jsonPatchOperation{
+				{Op: "test", Path: path, Value: existing},
+				{Op: "replace", Path: path, Value: *updated},
+			}}
+		}
+	}
+
+	last := len(current.Status.Conditions) - 1
+	return archivePatch{patchType: types.JSONPatchType, data: []jsonPatchOperation{
+		{Op: "test", Path: fmt.Sprintf("/status/conditions/%d", last), Value: current.Status.Conditions[last]},
+		{Op: "add", Path: "/status/conditions/-", Value: *updated},
+	}}
+}
+
+// errorsPatch creates a patch that appends the error messages to the errors in the status of the current supportArchive.
+func errorsPatch(current *v1.SupportArchive, errorMessages []string) archivePatch {
+	if len(current.Status.Errors) == 0 {
+		return firstListEntryPatch(current, "errors", errorMessages)
+	}
+
+	var operations []jsonPatchOperation
+	for _, message := range errorMessages {
+		operations = append(operations, jsonPatchOperation{Op: "add", Path: "/status/errors/-", Value: message})
+	}
+
+	return archivePatch{patchType: types.JSONPatchType, data: operations}
+}
+
+// firstListEntryPatch creates a merge patch for a list in the status that is still empty. As JSON patches cannot
+// append to a list that does not exist, the resource version is used as precondition instead.
+func firstListEntryPatch(current *v1.SupportArchive, field string, value any) archivePatch {
+	return archivePatch{patchType: types.MergePatchType, data: map[string]any{
+		"metadata": map[string]any{"resourceVersion": current.ResourceVersion},
+		"status":   map[string]any{field: value},
+	}}
+}
@@ -0,0 +1,212 @@
+package v1
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var testTransitionTime = metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+func testCondition(conditionType string, status metav1.ConditionStatus) metav1.Condition {
+	return metav1.Condition{Type: conditionType, Status: status, Reason: "Test", LastTransitionTime: testTransitionTime}
+}
+
+func Test_supportArchiveClient_PatchStatusCondition(t *testing.T) {
+	t.Run("should add first condition", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.PatchStatusCondition(testCtx, "mySupportArchive", testCondition(v1.ConditionVolumeInfoFetched, metav1.ConditionTrue))
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, result.Status.Conditions, 1)
+		assert.True(t, meta.IsStatusConditionTrue(fake.objects["mySupportArchive"].Status.Conditions, v1.ConditionVolumeInfoFetched))
+	})
+	t.Run("should keep condition set concurrently by another collector", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+		defer server.Close()
+		fake.onPatch = func(name string) {
+			fake.objects[name].Status.Conditions = []metav1.Condition{testCondition(v1.ConditionNodeInfoFetched, metav1.ConditionTrue)}
+		}
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.PatchStatusCondition(testCtx, "mySupportArchive", testCondition(v1.ConditionVolumeInfoFetched, metav1.ConditionTrue))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, meta.IsStatusConditionTrue(result.Status.Conditions, v1.ConditionNodeInfoFetched))
+		assert.True(t, meta.IsStatusConditionTrue(result.Status.Conditions, v1.ConditionVolumeInfoFetched))
+		assert.Equal(t, 2, fake.patches)
+	})
+	t.Run("should replace existing condition by type", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		supportArchive.Status.Conditions = []metav1.Condition{
+			testCondition(v1.ConditionNodeInfoFetched, metav1.ConditionTrue),
+			testCondition(v1.ConditionVolumeInfoFetched, metav1.ConditionFalse),
+		}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+		condition := testCondition(v1.ConditionVolumeInfoFetched, metav1.ConditionTrue)
+		condition.LastTransitionTime = metav1.NewTime(testTransitionTime.Add(time.Hour))
+
+		// when
+		result, err := sClient.PatchStatusCondition(testCtx, "mySupportArchive", condition)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, result.Status.Conditions, 2)
+		assert.True(t, meta.IsStatusConditionTrue(result.Status.Conditions, v1.ConditionNodeInfoFetched))
+		updated := meta.FindStatusCondition(result.Status.Conditions, v1.ConditionVolumeInfoFetched)
+		assert.Equal(t, metav1.ConditionTrue, updated.Status)
+		assert.True(t, condition.LastTransitionTime.Equal(&updated.LastTransitionTime))
+		assert.Equal(t, 1, fake.patches)
+	})
+	t.Run("should keep transition time if status does not change", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		supportArchive.Status.Conditions = []metav1.Condition{testCondition(v1.ConditionVolumeInfoFetched, metav1.ConditionTrue)}
+		_, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+		condition := testCondition(v1.ConditionVolumeInfoFetched, metav1.ConditionTrue)
+		condition.LastTransitionTime = metav1.NewTime(testTransitionTime.Add(time.Hour))
+		condition.Message = "fetched again"
+
+		// when
+		result, err := sClient.PatchStatusCondition(testCtx, "mySupportArchive", condition)
+
+		// then
+		require.NoError(t, err)
+		updated := meta.FindStatusCondition(result.Status.Conditions, v1.ConditionVolumeInfoFetched)
+		assert.Equal(t, "fetched again", updated.Message)
+		assert.True(t, testTransitionTime.Equal(&updated.LastTransitionTime))
+	})
+	t.Run("should fail on client error", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+		defer server.Close()
+		fake.failWith = http.StatusInternalServerError
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.PatchStatusCondition(testCtx, "mySupportArchive", testCondition(v1.ConditionVolumeInfoFetched, metav1.ConditionTrue))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to patch condition VolumeInfoFetched of supportArchive mySupportArchive")
+	})
+}
+
+func Test_supportArchiveClient_PatchStatusErrors(t *testing.T) {
+	t.Run("should add first errors", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.PatchStatusErrors(testCtx, "mySupportArchive", "error 1", "error 2")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"error 1", "error 2"}, result.Status.Errors)
+		assert.Equal(t, []string{"error 1", "error 2"}, fake.objects["mySupportArchive"].Status.Errors)
+	})
+	t.Run("should keep errors added concurrently", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+		defer server.Close()
+		fake.onPatch = func(name string) {
+			fake.objects[name].Status.Errors = []string{"concurrent error"}
+		}
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.PatchStatusErrors(testCtx, "mySupportArchive", "my error")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"concurrent error", "my error"}, result.Status.Errors)
+		assert.Equal(t, 2, fake.patches)
+	})
+	t.Run("should append to existing errors", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		supportArchive.Status.Errors = []string{"error 1"}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.PatchStatusErrors(testCtx, "mySupportArchive", "error 2")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"error 1", "error 2"}, result.Status.Errors)
+		assert.Equal(t, 1, fake.patches)
+	})
+	t.Run("should not patch without errors", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.PatchStatusErrors(testCtx, "mySupportArchive")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "mySupportArchive", result.Name)
+		assert.Equal(t, 0, fake.patches)
+	})
+}
+
+func Test_supportArchiveClient_PatchStatusDownloadPath(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+		supportArchive.Status.Errors = []string{"error 1"}
+		fake, server := newFakeServer(t, supportArchive)
+		defer server.Close()
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		result, err := sClient.PatchStatusDownloadPath(testCtx, "mySupportArchive", "/archives/mySupportArchive.zip")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "/archives/mySupportArchive.zip", result.Status.DownloadPath)
+		assert.Equal(t, []string{"error 1"}, fake.objects["mySupportArchive"].Status.Errors)
+	})
+	t.Run("should fail on client error", func(t *testing.T) {
+		// given
+		fake, server := newFakeServer(t, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+		defer server.Close()
+		fake.failWith = http.StatusInternalServerError
+		sClient := newFakeServerTestClient(t, server)
+
+		// when
+		_, err := sClient.PatchStatusDownloadPath(testCtx, "mySupportArchive", "/archives/mySupportArchive.zip")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to patch download path of supportArchive mySupportArchive")
+	})
+}
@@ -0,0 +1,118 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+
+const (
+	attributeNamespace = attribute.Key("k8s.namespace.name")
+	attributeName      = attribute.Key("k8s.supportarchive.name")
+	attributeVerb      = attribute.Key("k8s.verb")
+	attributeAttempt   = attribute.Key("retry.attempt")
+)
+
+type attemptKey struct{}
+
+// withAttempt marks all requests made with the returned context as the given retry attempt.
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+func attemptFrom(ctx context.Context) int {
+	attempt, ok := ctx.Value(attemptKey{}).(int)
+	if !ok {
+		return 1
+	}
+
+	return attempt
+}
+
+func newTracer(provider trace.TracerProvider) trace.Tracer {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+
+	return provider.Tracer(tracerName)
+}
+
+// startSpan starts a span for an operation of the client that consists of several requests.
+func (client *supportArchiveClient) startSpan(ctx context.Context, operation string, name string) (context.Context, trace.Span) {
+	return client.tracer.Start(ctx, "SupportArchive "+operation, trace.WithAttributes(
+		attributeNamespace.String(client.ns),
+		attributeName.String(name),
+	))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingTransport wraps the transport of the rest.Interface used by the client so that every request gets a span.
+func tracingTransport(tracer trace.Tracer) func(http.RoundTripper) http.RoundTripper {
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return &tracingRoundTripper{tracer: tracer, delegate: rt}
+	}
+}
+
+type tracingRoundTripper struct {
+	tracer   trace.Tracer
+	delegate http.RoundTripper
+}
+
+func (rt *tracingRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	verb := requestVerb(request)
+	namespace, name := requestObject(request)
+	ctx, span := rt.tracer.Start(request.Context(), "SupportArchive "+verb,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attributeNamespace.String(namespace),
+			attributeName.String(name),
+			attributeVerb.String(verb),
+			attributeAttempt.Int(attemptFrom(request.Context())),
+		),
+	)
+	defer span.End()
+
+	response, err := rt.delegate.RoundTrip(request.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+	if response.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, fmt.Sprintf("request failed with status code %d", response.StatusCode))
+	}
+
+	return response, nil
+}
+
+// requestObject returns the namespace and name of the support archive addressed by the request path.
+func requestObject(request *http.Request) (namespace string, name string) {
+	segments := strings.Split(strings.Trim(request.URL.Path, "/"), "/")
+	for i := 0; i < len(segments)-1; i++ {
+		switch strings.ToLower(segments[i]) {
+		case "namespaces":
+			namespace = segments[i+1]
+		case "supportarchives":
+			return namespace, segments[i+1]
+		}
+	}
+
+	return namespace, ""
+}
@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+func newTestTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)), exporter
+}
+
+func spanAttributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	result := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		result[kv.Key] = kv.Value
+	}
+
+	return result
+}
+
+func TestWithTracing(t *testing.T) {
+	t.Run("should create span for request", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Add("content-type", "application/json")
+			supportArchiveBytes, err := json.Marshal(&v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+			require.NoError(t, err)
+			_, err = writer.Write(supportArchiveBytes)
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		provider, exporter := newTestTracerProvider()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithTracing(provider))
+		require.NoError(t, err)
+
+		// when
+		_, err = client.SupportArchives("test").Create(testCtx, &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive"}}, metav1.CreateOptions{})
+
+		// then
+		require.NoError(t, err)
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "SupportArchive create", spans[0].Name)
+		attributes := spanAttributes(spans[0])
+		assert.Equal(t, "test", attributes[attributeNamespace].AsString())
+		assert.Equal(t, "create", attributes[attributeVerb].AsString())
+		assert.Equal(t, int64(1), attributes[attributeAttempt].AsInt64())
+	})
+	t.Run("should record retry attempts below the operation span", func(t *testing.T) {
+		// given
+		putRequestCounter := 0
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.Header().Add("content-type", "application/json")
+			if request.Method == http.MethodPut && putRequestCounter == 0 {
+				putRequestCounter++
+				writer.WriteHeader(http.StatusConflict)
+				_, err := writer.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Conflict","code":409}`))
+				require.NoError(t, err)
+				return
+			}
+			supportArchiveBytes, err := json.Marshal(&v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}})
+			require.NoError(t, err)
+			_, err = writer.Write(supportArchiveBytes)
+			require.NoError(t, err)
+		}))
+		defer server.Close()
+
+		provider, exporter := newTestTracerProvider()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithTracing(provider))
+		require.NoError(t, err)
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+
+		// when
+		_, err = client.SupportArchives("test").UpdateStatusWithRetry(testCtx, supportArchive, func(status v1.SupportArchiveStatus) v1.SupportArchiveStatus {
+			return status
+		}, metav1.UpdateOptions{})
+
+		// then
+		require.NoError(t, err)
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 4)
+		operation := spans[3]
+		assert.Equal(t, "SupportArchive UpdateStatusWithRetry", operation.Name)
+		assert.Equal(t, "mySupportArchive", spanAttributes(operation)[attributeName].AsString())
+
+		var attempts []int64
+		for _, span := range spans[:3] {
+			assert.Equal(t, operation.SpanContext.SpanID(), span.Parent.SpanID())
+			assert.Equal(t, "mySupportArchive", spanAttributes(span)[attributeName].AsString())
+			attempts = append(attempts, spanAttributes(span)[attributeAttempt].AsInt64())
+		}
+		assert.Equal(t, []int64{1, 2, 2}, attempts)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Equal(t, "SupportArchive get", spans[1].Name)
+	})
+	t.Run("should record error of finalizer update", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		provider, exporter := newTestTracerProvider()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithTracing(provider))
+		require.NoError(t, err)
+		supportArchive := &v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive", Namespace: "test"}}
+
+		// when
+		_, err = client.SupportArchives("test").AddFinalizer(testCtx, supportArchive, "cleanup")
+
+		// then
+		require.Error(t, err)
+		spans := exporter.GetSpans()
+		require.NotEmpty(t, spans)
+		operation := spans[len(spans)-1]
+		assert.Equal(t, "SupportArchive AddFinalizer", operation.Name)
+		assert.Equal(t, codes.Error, operation.Status.Code)
+		assert.Contains(t, operation.Status.Description, "failed to add finalizer cleanup")
+	})
+}
+
+func Test_requestObject(t *testing.T) {
+	t.Run("should return namespace and name", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives/a/status", nil)
+
+		namespace, name := requestObject(request)
+
+		assert.Equal(t, "test", namespace)
+		assert.Equal(t, "a", name)
+	})
+	t.Run("should return namespace only for collections", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/apis/k8s.cloudogu.com/v1/namespaces/test/supportarchives", nil)
+
+		namespace, name := requestObject(request)
+
+		assert.Equal(t, "test", namespace)
+		assert.Empty(t, name)
+	})
+}
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const (
+	defaultWaitInterval = 5 * time.Second
+	defaultWaitTimeout  = 30 * time.Minute
+)
+
+var (
+	// ErrCancelled is returned by Wait if the supportArchive was cancelled before the condition became true.
+	ErrCancelled = errors.New("the supportArchive was cancelled")
+	// ErrRejected is returned by Wait if the supportArchive was rejected before the condition became true.
+	ErrRejected = errors.New("the supportArchive was rejected")
+)
+
+// WaitOptions configure how to wait for SupportArchives.
+type WaitOptions struct {
+	// Condition is the condition type that has to become true. Defaults to v1.ConditionSupportArchiveCreated.
+	Condition string
+	// Interval between two checks. Defaults to 5 seconds.
+	Interval time.Duration
+	// Timeout for the whole wait. Defaults to 30 minutes.
+	Timeout time.Duration
+}
+
+// WithDefaults returns the options with the defaults set for all empty fields.
+func (opts WaitOptions) WithDefaults() WaitOptions {
+	if opts.Condition == "" {
+		opts.Condition = v1.ConditionSupportArchiveCreated
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWaitInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultWaitTimeout
+	}
+
+	return opts
+}
+
+// Wait fetches the supportArchive with get until the condition of the options is true. As cancelled and rejected
+// supportArchives never change again, it fails with ErrCancelled or ErrRejected for them, unless the condition is
+// already true. It returns the last fetched supportArchive, also on errors if there is one. Errors of get end the wait.
+// Wait lets other clients, like the controller-runtime helpers in ctrlclient, wait exactly like the REST clients.
+func Wait(ctx context.Context, get func(ctx context.Context) (*v1.SupportArchive, error), opts WaitOptions) (*v1.SupportArchive, error) {
+	opts = opts.WithDefaults()
+
+	var current *v1.SupportArchive
+	err := wait.PollUntilContextTimeout(ctx, opts.Interval, opts.Timeout, true, func(ctx context.Context) (bool, error) {
+		supportArchive, err := get(ctx)
+		if err != nil {
+			return false, err
+		}
+		current = supportArchive
+
+		if meta.IsStatusConditionTrue(supportArchive.Status.Conditions, opts.Condition) {
+			return true, nil
+		}
+		return false, checkTerminal(supportArchive)
+	})
+
+	return current, err
+}
+
+// checkTerminal returns an error if the supportArchive was cancelled or rejected.
+func checkTerminal(supportArchive *v1.SupportArchive) error {
+	if supportArchive.Status.Phase == v1.StatusPhaseCancelled || meta.IsStatusConditionTrue(supportArchive.Status.Conditions, v1.ConditionCancelled) {
+		return ErrCancelled
+	}
+	if approval := supportArchive.Status.Approval; approval != nil && approval.Decision == v1.ApprovalDecisionRejected {
+		return fmt.Errorf("%w by %s", ErrRejected, approval.DecidedBy)
+	}
+
+	return nil
+}
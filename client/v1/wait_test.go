@@ -0,0 +1,99 @@
+package v1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var testWaitOptions = WaitOptions{Interval: time.Millisecond, Timeout: time.Second}
+
+// getSequence returns the supportArchives in order and repeats the last one.
+func getSequence(archives ...*v1.SupportArchive) (func(ctx context.Context) (*v1.SupportArchive, error), *int) {
+	gets := 0
+	return func(context.Context) (*v1.SupportArchive, error) {
+		gets++
+		return archives[min(gets, len(archives))-1], nil
+	}, &gets
+}
+
+func TestWait(t *testing.T) {
+	t.Run("should return once the condition is true", func(t *testing.T) {
+		// given
+		get, gets := getSequence(&v1.SupportArchive{}, archiveWithCondition(v1.ConditionSupportArchiveCreated))
+
+		// when
+		result, err := Wait(testCtx, get, testWaitOptions)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, *gets)
+		assert.Equal(t, "mySupportArchive", result.Name)
+	})
+	t.Run("should fail if cancelled", func(t *testing.T) {
+		// given
+		cancelled := &v1.SupportArchive{Status: v1.SupportArchiveStatus{Phase: v1.StatusPhaseCancelled}}
+		get, gets := getSequence(&v1.SupportArchive{}, cancelled)
+
+		// when
+		result, err := Wait(testCtx, get, testWaitOptions)
+
+		// then
+		require.ErrorIs(t, err, ErrCancelled)
+		assert.Equal(t, 2, *gets)
+		assert.Same(t, cancelled, result)
+	})
+	t.Run("should fail if rejected", func(t *testing.T) {
+		// given
+		rejected := &v1.SupportArchive{Status: v1.SupportArchiveStatus{Approval: &v1.ApprovalStatus{Decision: v1.ApprovalDecisionRejected, DecidedBy: "bob"}}}
+		get, _ := getSequence(rejected)
+
+		// when
+		_, err := Wait(testCtx, get, testWaitOptions)
+
+		// then
+		require.ErrorIs(t, err, ErrRejected)
+		assert.EqualError(t, err, "the supportArchive was rejected by bob")
+	})
+	t.Run("should succeed when waiting for the cancellation", func(t *testing.T) {
+		// given
+		get, _ := getSequence(archiveWithCondition(v1.ConditionCancelled))
+
+		// when
+		_, err := Wait(testCtx, get, WaitOptions{Condition: v1.ConditionCancelled, Interval: time.Millisecond, Timeout: time.Second})
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail on timeout and return the last supportArchive", func(t *testing.T) {
+		// given
+		get, _ := getSequence(&v1.SupportArchive{ObjectMeta: metav1.ObjectMeta{Name: "mySupportArchive"}})
+
+		// when
+		result, err := Wait(testCtx, get, WaitOptions{Interval: time.Millisecond, Timeout: 10 * time.Millisecond})
+
+		// then
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, "mySupportArchive", result.Name)
+	})
+	t.Run("should fail if get fails", func(t *testing.T) {
+		// when
+		result, err := Wait(testCtx, func(context.Context) (*v1.SupportArchive, error) { return nil, assert.AnError }, testWaitOptions)
+
+		// then
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, result)
+	})
+}
+
+func TestWaitOptions_WithDefaults(t *testing.T) {
+	assert.Equal(t, WaitOptions{Condition: v1.ConditionSupportArchiveCreated, Interval: 5 * time.Second, Timeout: 30 * time.Minute}, WaitOptions{}.WithDefaults())
+	assert.Equal(t, testWaitOptions.Interval, testWaitOptions.WithDefaults().Interval)
+}
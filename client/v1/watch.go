@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// ArchiveEvent is a change of a supportArchive observed by WatchArchives.
+type ArchiveEvent struct {
+	// Type is watch.Added, watch.Modified or watch.Deleted.
+	Type           watch.EventType
+	SupportArchive *v1.SupportArchive
+}
+
+// WatchArchives returns an iterator over the changes of the supportArchives that match the list options.
+//
+// The iterator keeps track of the resource version and watches again from there whenever the API server ends the
+// watch, e.g. because of a timeout. Bookmarks are requested to keep the resource version recent, but not returned.
+// If the resource version is too old (410 Gone), the iterator watches again without resource version, so that the
+// API server sends the current state of all supportArchives as watch.Added events. Deletions that happened in the
+// meantime are not reported in this case. Before watching again, the iterator waits according to the backoff of the
+// RetryPolicy of the client, which starts over once a watch delivered an event.
+//
+// The iteration ends if the context is done or after the first error, which is returned with an empty event.
+func (client *supportArchiveClient) WatchArchives(ctx context.Context, opts metav1.ListOptions) iter.Seq2[ArchiveEvent, error] {
+	return func(yield func(ArchiveEvent, error) bool) {
+		opts.AllowWatchBookmarks = true
+		backoff := client.retryPolicy.Backoff
+		for reconnect := false; ctx.Err() == nil; reconnect = true {
+			if reconnect {
+				if !sleep(ctx, backoff.Step()) {
+					return
+				}
+				client.metrics.watchReconnect()
+			}
+
+			watcher, err := client.Watch(ctx, opts)
+			if isExpired(err) && opts.ResourceVersion != "" {
+				opts.ResourceVersion = ""
+				continue
+			}
+			if err != nil {
+				yield(ArchiveEvent{}, fmt.Errorf("failed to watch supportArchives: %w", err))
+				return
+			}
+
+			resourceVersion, received, stopped, err := consumeWatch(watcher, opts.ResourceVersion, yield)
+			watcher.Stop()
+			if stopped {
+				return
+			}
+			if received {
+				backoff = client.retryPolicy.Backoff
+			}
+			if isExpired(err) && resourceVersion != "" {
+				opts.ResourceVersion = ""
+				continue
+			}
+			if err != nil {
+				yield(ArchiveEvent{}, fmt.Errorf("failed to watch supportArchives: %w", err))
+				return
+			}
+			opts.ResourceVersion = resourceVersion
+		}
+	}
+}
+
+// consumeWatch yields the events of the watcher until its result channel is closed. It returns the last observed
+// resource version, whether a supportArchive or bookmark was received, whether the caller stopped the iteration, and
+// the error reported by the watch.
+func consumeWatch(watcher watch.Interface, resourceVersion string, yield func(ArchiveEvent, error) bool) (string, bool, bool, error) {
+	received := false
+	for event := range watcher.ResultChan() {
+		switch event.Type {
+		case watch.Error:
+			return resourceVersion, received, false, apierrors.FromObject(event.Object)
+		case watch.Bookmark, watch.Added, watch.Modified, watch.Deleted:
+			supportArchive, ok := event.Object.(*v1.SupportArchive)
+			if !ok {
+				return resourceVersion, received, false, fmt.Errorf("unexpected object of type %T in watch event", event.Object)
+			}
+			resourceVersion = supportArchive.ResourceVersion
+			received = true
+			if event.Type == watch.Bookmark {
+				continue
+			}
+			if !yield(ArchiveEvent{Type: event.Type, SupportArchive: supportArchive}, nil) {
+				return resourceVersion, received, true, nil
+			}
+		}
+	}
+
+	return resourceVersion, received, false, nil
+}
+
+// sleep waits for the duration and returns false if the context is done before.
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
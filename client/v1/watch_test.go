@@ -0,0 +1,230 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+type testWatchEvent struct {
+	Type   watch.EventType `json:"type"`
+	Object runtime.Object  `json:"object"`
+}
+
+func watchedArchive(name string, resourceVersion string) *v1.SupportArchive {
+	return &v1.SupportArchive{
+		TypeMeta:   metav1.TypeMeta{Kind: "SupportArchive", APIVersion: v1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", ResourceVersion: resourceVersion},
+	}
+}
+
+func goneStatus() *metav1.Status {
+	return &metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Reason:   metav1.StatusReasonExpired,
+		Code:     http.StatusGone,
+		Message:  "too old resource version",
+	}
+}
+
+// newWatchServer serves one list of watch events per watch request and records the requested resource versions.
+func newWatchServer(t *testing.T, connections ...[]testWatchEvent) (*httptest.Server, *[]string) {
+	var resourceVersions []string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "true", request.URL.Query().Get("watch"))
+		assert.Equal(t, "true", request.URL.Query().Get("allowWatchBookmarks"))
+		resourceVersions = append(resourceVersions, request.URL.Query().Get("resourceVersion"))
+
+		writer.Header().Add("content-type", "application/json")
+		if len(resourceVersions) > len(connections) {
+			<-request.Context().Done()
+			return
+		}
+		encoder := json.NewEncoder(writer)
+		for _, event := range connections[len(resourceVersions)-1] {
+			require.NoError(t, encoder.Encode(event))
+		}
+	}))
+
+	return server, &resourceVersions
+}
+
+func Test_supportArchiveClient_WatchArchives(t *testing.T) {
+	t.Run("should watch again from the last resource version", func(t *testing.T) {
+		// given
+		server, resourceVersions := newWatchServer(t,
+			[]testWatchEvent{
+				{Type: watch.Added, Object: watchedArchive("a", "1")},
+				{Type: watch.Bookmark, Object: watchedArchive("", "5")},
+			},
+			[]testWatchEvent{
+				{Type: watch.Modified, Object: watchedArchive("a", "6")},
+				{Type: watch.Deleted, Object: watchedArchive("a", "7")},
+			},
+		)
+		defer server.Close()
+		registry := prometheus.NewRegistry()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithMetrics(registry), WithRetryPolicy(testRetryPolicy(0, nil)))
+		require.NoError(t, err)
+
+		// when
+		var events []watch.EventType
+		for event, err := range client.SupportArchives("test").WatchArchives(testCtx, metav1.ListOptions{}) {
+			require.NoError(t, err)
+			assert.Equal(t, "a", event.SupportArchive.Name)
+			events = append(events, event.Type)
+			if len(events) == 3 {
+				break
+			}
+		}
+
+		// then
+		assert.Equal(t, []watch.EventType{watch.Added, watch.Modified, watch.Deleted}, events)
+		assert.Equal(t, []string{"", "5"}, *resourceVersions)
+		metrics, err := newClientMetrics(registry)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.watchReconnects))
+	})
+	t.Run("should watch without resource version after 410 Gone", func(t *testing.T) {
+		// given
+		server, resourceVersions := newWatchServer(t,
+			[]testWatchEvent{
+				{Type: watch.Added, Object: watchedArchive("a", "1")},
+				{Type: watch.Error, Object: goneStatus()},
+			},
+			[]testWatchEvent{
+				{Type: watch.Added, Object: watchedArchive("b", "9")},
+			},
+		)
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithRetryPolicy(testRetryPolicy(0, nil)))
+		require.NoError(t, err)
+
+		// when
+		var names []string
+		for event, err := range client.SupportArchives("test").WatchArchives(testCtx, metav1.ListOptions{ResourceVersion: "1"}) {
+			require.NoError(t, err)
+			names = append(names, event.SupportArchive.Name)
+			if len(names) == 2 {
+				break
+			}
+		}
+
+		// then
+		assert.Equal(t, []string{"a", "b"}, names)
+		assert.Equal(t, []string{"1", ""}, *resourceVersions)
+	})
+	t.Run("should back off between reconnects and start over after events", func(t *testing.T) {
+		// given
+		server, resourceVersions := newWatchServer(t,
+			[]testWatchEvent{},
+			[]testWatchEvent{},
+			[]testWatchEvent{{Type: watch.Added, Object: watchedArchive("a", "1")}},
+			[]testWatchEvent{},
+			[]testWatchEvent{{Type: watch.Added, Object: watchedArchive("b", "2")}},
+		)
+		defer server.Close()
+		policy := RetryPolicy{Backoff: wait.Backoff{Duration: 20 * time.Millisecond, Factor: 4, Steps: 5}}
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithRetryPolicy(policy))
+		require.NoError(t, err)
+		start := time.Now()
+
+		// when
+		var names []string
+		for event, err := range client.SupportArchives("test").WatchArchives(testCtx, metav1.ListOptions{}) {
+			require.NoError(t, err)
+			names = append(names, event.SupportArchive.Name)
+			if len(names) == 2 {
+				break
+			}
+		}
+
+		// then
+		assert.Equal(t, []string{"a", "b"}, names)
+		assert.Len(t, *resourceVersions, 5)
+		// 20ms + 80ms before the first event and again before the second one, instead of 20ms + 80ms + 320ms + 1280ms
+		elapsed := time.Since(start)
+		assert.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+		assert.Less(t, elapsed, time.Second)
+	})
+	t.Run("should stop backing off when the context is done", func(t *testing.T) {
+		// given
+		server, _ := newWatchServer(t, []testWatchEvent{})
+		defer server.Close()
+		policy := RetryPolicy{Backoff: wait.Backoff{Duration: time.Hour}}
+		client, err := NewForConfig(&rest.Config{Host: server.URL}, WithRetryPolicy(policy))
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(testCtx, 50*time.Millisecond)
+		defer cancel()
+
+		// when
+		count := 0
+		for range client.SupportArchives("test").WatchArchives(ctx, metav1.ListOptions{}) {
+			count++
+		}
+
+		// then
+		assert.Zero(t, count)
+	})
+	t.Run("should return other watch errors", func(t *testing.T) {
+		// given
+		internalError := &metav1.Status{
+			TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonInternalError,
+			Code:     http.StatusInternalServerError,
+			Message:  "etcd unavailable",
+		}
+		server, _ := newWatchServer(t, []testWatchEvent{{Type: watch.Error, Object: internalError}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+
+		// when
+		var errs []error
+		for _, err := range client.SupportArchives("test").WatchArchives(testCtx, metav1.ListOptions{}) {
+			errs = append(errs, err)
+		}
+
+		// then
+		require.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "failed to watch supportArchives: etcd unavailable")
+	})
+	t.Run("should end when the context is done", func(t *testing.T) {
+		// given
+		server, _ := newWatchServer(t, []testWatchEvent{{Type: watch.Added, Object: watchedArchive("a", "1")}})
+		defer server.Close()
+		client, err := NewForConfig(&rest.Config{Host: server.URL})
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(testCtx)
+		defer cancel()
+
+		// when
+		count := 0
+		for _, err := range client.SupportArchives("test").WatchArchives(ctx, metav1.ListOptions{}) {
+			require.NoError(t, err)
+			count++
+			cancel()
+		}
+
+		// then
+		assert.Equal(t, 1, count)
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+func newApproveCmd(global *globalOptions) *cobra.Command {
+	return newDecisionCmd(global, "approve", "approved", "Approve a SupportArchive that requires approval. Its requester cannot approve it.",
+		clientv1.SupportArchiveInterface.Approve)
+}
+
+func newRejectCmd(global *globalOptions) *cobra.Command {
+	return newDecisionCmd(global, "reject", "rejected", "Reject a SupportArchive that requires approval",
+		clientv1.SupportArchiveInterface.Reject)
+}
+
+type decideFunc func(client clientv1.SupportArchiveInterface, ctx context.Context, name string, reason string) (*v1.SupportArchive, error)
+
+// newDecisionCmd creates a command that approves or rejects a SupportArchive with the given decide function.
+func newDecisionCmd(global *globalOptions, verb string, decided string, short string, decide decideFunc) *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   verb + " NAME",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supportArchives, err := global.supportArchives()
+			if err != nil {
+				return err
+			}
+
+			_, err = decide(supportArchives, cmd.Context(), args[0], reason)
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "supportarchive %q %s\n", args[0], decided)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&reason, "reason", "", "Reason for the decision")
+
+	return cmd
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	"github.com/cloudogu/k8s-support-archive-lib/builder"
+)
+
+type createOptions struct {
+	excluded        v1.ExcludedContents
+	startTime       string
+	endTime         string
+	since           time.Duration
+	skipAccessCheck bool
+	requireApproval bool
+	requester       string
+	ticket          string
+	reason          string
+}
+
+func newCreateCmd(global *globalOptions) *cobra.Command {
+	opts := &createOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a SupportArchive",
+		Long: "Create a SupportArchive. All contents are included unless excluded by flags.\n" +
+			"The timeframe defaults to the last 24 hours and can be set with --since or --start-time and --end-time.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supportArchiveClient, namespace, err := global.supportArchiveClient()
+			if err != nil {
+				return err
+			}
+
+			if opts.requireApproval {
+				opts.requester, err = supportArchiveClient.CurrentUser(cmd.Context())
+				if err != nil {
+					return err
+				}
+			}
+
+			archive, err := opts.supportArchive(args[0], time.Now())
+			if err != nil {
+				return err
+			}
+
+			if !opts.skipAccessCheck {
+				err = global.checkAccess(cmd.Context(), "create")
+				if err != nil {
+					return fmt.Errorf("cannot create SupportArchive %s: %w", args[0], err)
+				}
+			}
+
+			created, err := supportArchiveClient.SupportArchives(namespace).Create(cmd.Context(), archive, metav1.CreateOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to create SupportArchive %s: %w", args[0], err)
+			}
+
+			return printArchive(cmd.OutOrStdout(), global.output, created)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.excluded.SystemState, "exclude-system-state", false, "Exclude all Kubernetes resources (excluding Secrets) with label app: ces")
+	flags.BoolVar(&opts.excluded.SensitiveData, "exclude-sensitive-data", false, "Exclude Secrets with label app: ces")
+	flags.BoolVar(&opts.excluded.Events, "exclude-events", false, "Exclude Kubernetes events")
+	flags.BoolVar(&opts.excluded.Logs, "exclude-logs", false, "Exclude application logs")
+	flags.BoolVar(&opts.excluded.VolumeInfo, "exclude-volume-info", false, "Exclude metrics about volumes")
+	flags.BoolVar(&opts.excluded.SystemInfo, "exclude-system-info", false, "Exclude information about the system like the kubernetes version and nodes")
+	flags.StringVar(&opts.startTime, "start-time", "", "Start of the content timeframe in RFC3339 format")
+	flags.StringVar(&opts.endTime, "end-time", "", "End of the content timeframe in RFC3339 format (default now)")
+	flags.DurationVar(&opts.since, "since", 24*time.Hour, "Length of the content timeframe ending at --end-time, used if --start-time is not set")
+	flags.StringVar(&opts.ticket, "ticket", "", "Reference of the support ticket the SupportArchive is created for")
+	flags.StringVar(&opts.reason, "reason", "", "Description why the SupportArchive is created")
+	flags.BoolVar(&opts.requireApproval, "require-approval", false, "Collect the contents only after another user approved the SupportArchive")
+	flags.BoolVar(&opts.skipAccessCheck, "skip-access-check", false, "Do not check with a SelfSubjectAccessReview whether SupportArchives may be created")
+
+	return cmd
+}
+
+// supportArchive builds the SupportArchive from the flags.
+func (opts *createOptions) supportArchive(name string, now time.Time) (*v1.SupportArchive, error) {
+	archiveBuilder := builder.NewSupportArchive(name, "").
+		Exclude(opts.excludedCategories()...).
+		ForTicket(opts.ticket).
+		WithReason(opts.reason).
+		WithClock(func() time.Time { return now })
+	if opts.requireApproval {
+		archiveBuilder.RequireApproval(opts.requester)
+	}
+
+	if opts.endTime != "" {
+		endTime, err := time.Parse(time.RFC3339, opts.endTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end time %q: %w", opts.endTime, err)
+		}
+		archiveBuilder.EndingAt(endTime)
+	}
+
+	if opts.startTime == "" {
+		archiveBuilder.Last(opts.since)
+	} else {
+		startTime, err := time.Parse(time.RFC3339, opts.startTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time %q: %w", opts.startTime, err)
+		}
+		archiveBuilder.StartingAt(startTime)
+	}
+
+	return archiveBuilder.Build()
+}
+
+// excludedCategories returns the content categories excluded by flags.
+func (opts *createOptions) excludedCategories() []v1.ContentCategory {
+	flags := map[v1.ContentCategory]bool{
+		v1.ContentSystemState:   opts.excluded.SystemState,
+		v1.ContentSensitiveData: opts.excluded.SensitiveData,
+		v1.ContentEvents:        opts.excluded.Events,
+		v1.ContentLogs:          opts.excluded.Logs,
+		v1.ContentVolumeInfo:    opts.excluded.VolumeInfo,
+		v1.ContentSystemInfo:    opts.excluded.SystemInfo,
+	}
+
+	var categories []v1.ContentCategory
+	for category, excluded := range flags {
+		if excluded {
+			categories = append(categories, category)
+		}
+	}
+
+	return categories
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newDeleteCmd(global *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete NAME...",
+		Short: "Delete SupportArchives",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supportArchives, err := global.supportArchives()
+			if err != nil {
+				return err
+			}
+
+			var errs []error
+			for _, name := range args {
+				err = supportArchives.Delete(cmd.Context(), name, metav1.DeleteOptions{})
+				if err != nil {
+					errs = append(errs, fmt.Errorf("failed to delete SupportArchive %s: %w", name, err))
+					continue
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "supportarchive %q deleted\n", name)
+			}
+
+			return errors.Join(errs...)
+		},
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newDescribeCmd(global *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe NAME",
+		Short: "Show details of a SupportArchive including its conditions and errors",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supportArchives, err := global.supportArchives()
+			if err != nil {
+				return err
+			}
+
+			archive, err := supportArchives.Get(cmd.Context(), args[0], metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to get SupportArchive %s: %w", args[0], err)
+			}
+
+			if global.output == outputTable {
+				return printDescription(cmd.OutOrStdout(), archive)
+			}
+
+			return printArchive(cmd.OutOrStdout(), global.output, archive)
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+type downloadOptions struct {
+	file             string
+	resume           bool
+	skipVerification bool
+	service          clientv1.ServiceReference
+}
+
+func newDownloadCmd(global *globalOptions) *cobra.Command {
+	opts := &downloadOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "download NAME",
+		Short: "Download the archive of a SupportArchive",
+		Long: "Download the archive of a SupportArchive through the API server and verify it against the digest in its status.\n" +
+			"Use --file - to write the archive to stdout.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supportArchives, err := global.supportArchives()
+			if err != nil {
+				return err
+			}
+
+			file := opts.file
+			if file == "" {
+				archive, err := supportArchives.Get(cmd.Context(), args[0], metav1.GetOptions{})
+				if err != nil {
+					return fmt.Errorf("failed to get SupportArchive %s: %w", args[0], err)
+				}
+				file = defaultDownloadFile(args[0], archive.Status.DownloadPath)
+			}
+
+			downloadOpts := clientv1.DownloadOptions{SkipVerification: opts.skipVerification}
+			if opts.service.Name != "" {
+				downloadOpts.Service = &opts.service
+			}
+
+			if file == "-" {
+				return supportArchives.Download(cmd.Context(), args[0], cmd.OutOrStdout(), downloadOpts)
+			}
+
+			return opts.downloadToFile(cmd, supportArchives, args[0], file, downloadOpts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.file, "file", "f", "", "The file to write the archive to (default is the file name of the download path)")
+	flags.BoolVar(&opts.resume, "resume", false, "Resume the download if the file already exists")
+	flags.BoolVar(&opts.skipVerification, "skip-verification", false, "Skip the verification of size and digest, required for archives without a digest")
+	flags.StringVar(&opts.service.Name, "service", "", "The service serving the archive if the download path is not an API server path")
+	flags.StringVar(&opts.service.Namespace, "service-namespace", "", "The namespace of the service (default is the namespace of the SupportArchive)")
+	flags.StringVar(&opts.service.Port, "service-port", "", "The port of the service")
+
+	return cmd
+}
+
+func (opts *downloadOptions) downloadToFile(cmd *cobra.Command, supportArchives clientv1.SupportArchiveInterface, name string, file string, downloadOpts clientv1.DownloadOptions) error {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if opts.resume {
+		flags = os.O_CREATE | os.O_RDWR | os.O_APPEND
+	}
+
+	out, err := os.OpenFile(file, flags, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %w", file, err)
+	}
+
+	if opts.resume {
+		downloadOpts.ResumeFrom = out
+	}
+
+	err = supportArchives.Download(cmd.Context(), name, out, downloadOpts)
+	err = errors.Join(err, out.Close())
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "supportarchive %q downloaded to %s\n", name, file)
+	return nil
+}
+
+func defaultDownloadFile(name string, downloadPath string) string {
+	parsed, err := url.Parse(downloadPath)
+	if err == nil {
+		base := path.Base(parsed.Path)
+		if base != "." && base != "/" {
+			return base
+		}
+	}
+
+	return name + ".zip"
+}
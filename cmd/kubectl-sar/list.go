@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+func newListCmd(global *globalOptions) *cobra.Command {
+	var selector string
+	var filter clientv1.ListFilter
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List SupportArchives",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supportArchives, err := global.supportArchives()
+			if err != nil {
+				return err
+			}
+
+			list, err := listFiltered(cmd.Context(), supportArchives, metav1.ListOptions{LabelSelector: selector}, filter)
+			if err != nil {
+				return fmt.Errorf("failed to list SupportArchives: %w", err)
+			}
+
+			return printArchiveList(cmd.OutOrStdout(), global.output, list)
+		},
+	}
+
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "Label selector to filter SupportArchives")
+	cmd.Flags().StringVar(&filter.Requester, "requester", "", "Only list SupportArchives of the requester")
+	cmd.Flags().StringVar(&filter.Ticket, "ticket", "", "Only list SupportArchives of the support ticket")
+
+	return cmd
+}
+
+// listFiltered lists the SupportArchives matching the filter with field selectors. Clusters before Kubernetes 1.31
+// reject field selectors for SupportArchives, so the filter is applied on the client instead.
+func listFiltered(ctx context.Context, supportArchives clientv1.SupportArchiveInterface, opts metav1.ListOptions, filter clientv1.ListFilter) (*v1.SupportArchiveList, error) {
+	if filter == (clientv1.ListFilter{}) {
+		return supportArchives.List(ctx, opts)
+	}
+
+	filteredOpts, err := filter.Apply(opts)
+	if err != nil {
+		return nil, err
+	}
+	list, err := supportArchives.List(ctx, filteredOpts)
+	if !apierrors.IsBadRequest(err) {
+		return list, err
+	}
+
+	list, err = supportArchives.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	matching := list.Items[:0]
+	for _, archive := range list.Items {
+		if filter.Matches(&archive) {
+			matching = append(matching, archive)
+		}
+	}
+	list.Items = matching
+
+	return list, nil
+}
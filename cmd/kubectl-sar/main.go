@@ -0,0 +1,15 @@
+// Command kubectl-sar is a kubectl plugin to manage SupportArchives.
+//
+// Install it by placing the binary in your PATH and call it as `kubectl sar`.
+package main
+
+import (
+	"os"
+)
+
+func main() {
+	err := newRootCmd().Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const (
+	kindSupportArchive     = "SupportArchive"
+	kindSupportArchiveList = "SupportArchiveList"
+)
+
+// printArchive prints a single SupportArchive in the given format.
+func printArchive(writer io.Writer, format string, archive *v1.SupportArchive) error {
+	archive = archive.DeepCopy()
+	archive.APIVersion = v1.GroupVersion.String()
+	archive.Kind = kindSupportArchive
+
+	switch format {
+	case outputJSON:
+		return printJSON(writer, archive)
+	case outputYAML:
+		return printYAML(writer, archive)
+	default:
+		return printTable(writer, []v1.SupportArchive{*archive})
+	}
+}
+
+// printArchiveList prints a list of SupportArchives in the given format.
+func printArchiveList(writer io.Writer, format string, list *v1.SupportArchiveList) error {
+	list = list.DeepCopy()
+	list.APIVersion = v1.GroupVersion.String()
+	list.Kind = kindSupportArchiveList
+
+	switch format {
+	case outputJSON:
+		return printJSON(writer, list)
+	case outputYAML:
+		return printYAML(writer, list)
+	default:
+		return printTable(writer, list.Items)
+	}
+}
+
+func printJSON(writer io.Writer, obj any) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(obj)
+}
+
+func printYAML(writer io.Writer, obj any) error {
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(out)
+	return err
+}
+
+func printTable(writer io.Writer, archives []v1.SupportArchive) error {
+	tw := tabwriter.NewWriter(writer, 0, 8, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tCREATED\tDOWNLOAD PATH\tERRORS\tAGE")
+	for _, archive := range archives {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n",
+			archive.Name,
+			conditionStatus(archive.Status.Conditions, v1.ConditionSupportArchiveCreated),
+			valueOrNone(archive.Status.DownloadPath),
+			len(archive.Status.Errors),
+			age(archive.CreationTimestamp),
+		)
+	}
+
+	return tw.Flush()
+}
+
+// printDescription prints a human-readable description of the SupportArchive including its conditions and errors.
+func printDescription(writer io.Writer, archive *v1.SupportArchive) error {
+	tw := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	excluded := archive.Spec.ExcludedContents
+	timeframe := archive.Spec.ContentTimeframe
+
+	_, _ = fmt.Fprintf(tw, "Name:\t%s\n", archive.Name)
+	_, _ = fmt.Fprintf(tw, "Namespace:\t%s\n", archive.Namespace)
+	_, _ = fmt.Fprintf(tw, "Created:\t%s\n", formatTime(archive.CreationTimestamp))
+	_, _ = fmt.Fprintf(tw, "Requester:\t%s\n", valueOrNone(archive.Spec.Requester))
+	_, _ = fmt.Fprintf(tw, "Ticket:\t%s\n", valueOrNone(archive.Spec.Ticket))
+	_, _ = fmt.Fprintf(tw, "Reason:\t%s\n", valueOrNone(archive.Spec.Reason))
+	_, _ = fmt.Fprintln(tw, "Excluded Contents:")
+	_, _ = fmt.Fprintf(tw, "  System State:\t%t\n", excluded.SystemState)
+	_, _ = fmt.Fprintf(tw, "  Sensitive Data:\t%t\n", excluded.SensitiveData)
+	_, _ = fmt.Fprintf(tw, "  Events:\t%t\n", excluded.Events)
+	_, _ = fmt.Fprintf(tw, "  Logs:\t%t\n", excluded.Logs)
+	_, _ = fmt.Fprintf(tw, "  Volume Info:\t%t\n", excluded.VolumeInfo)
+	_, _ = fmt.Fprintf(tw, "  System Info:\t%t\n", excluded.SystemInfo)
+	_, _ = fmt.Fprintln(tw, "Content Timeframe:")
+	_, _ = fmt.Fprintf(tw, "  Start Time:\t%s\n", formatTime(timeframe.StartTime))
+	_, _ = fmt.Fprintf(tw, "  End Time:\t%s\n", formatTime(timeframe.EndTime))
+	_, _ = fmt.Fprintf(tw, "Phase:\t%s\n", valueOrNone(string(archive.Status.Phase)))
+	if archive.Spec.Cancel {
+		_, _ = fmt.Fprintln(tw, "Cancel:\ttrue")
+	}
+	_, _ = fmt.Fprintf(tw, "Download Path:\t%s\n", valueOrNone(archive.Status.DownloadPath))
+	if archive.Status.Digest != "" {
+		_, _ = fmt.Fprintf(tw, "Digest:\t%s\n", archive.Status.Digest)
+		_, _ = fmt.Fprintf(tw, "Size:\t%d\n", archive.Status.Size)
+	}
+	if archive.Spec.RequiresApproval {
+		_, _ = fmt.Fprintf(tw, "Approval:\t%s\n", formatApproval(archive.Status.Approval))
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintln(writer, "Conditions:")
+	if len(archive.Status.Conditions) == 0 {
+		_, _ = fmt.Fprintln(writer, "  <none>")
+	} else {
+		tw = tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "  TYPE\tSTATUS\tREASON\tLAST TRANSITION\tMESSAGE")
+		for _, condition := range archive.Status.Conditions {
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, formatTime(condition.LastTransitionTime), condition.Message)
+		}
+		err = tw.Flush()
+		if err != nil {
+			return err
+		}
+	}
+
+	_, _ = fmt.Fprintln(writer, "Errors:")
+	if len(archive.Status.Errors) == 0 {
+		_, _ = fmt.Fprintln(writer, "  <none>")
+	}
+	for _, archiveErr := range archive.Status.Errors {
+		_, _ = fmt.Fprintf(writer, "  - %s\n", strings.ReplaceAll(archiveErr, "\n", "\n    "))
+	}
+
+	return nil
+}
+
+func conditionStatus(conditions []metav1.Condition, conditionType string) string {
+	condition := meta.FindStatusCondition(conditions, conditionType)
+	if condition == nil {
+		return string(metav1.ConditionUnknown)
+	}
+
+	return string(condition.Status)
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+
+	return value
+}
+
+func formatTime(t metav1.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func age(t metav1.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+
+	return duration.HumanDuration(time.Since(t.Time))
+}
+
+func formatApproval(approval *v1.ApprovalStatus) string {
+	if approval == nil {
+		return "Pending"
+	}
+
+	formatted := fmt.Sprintf("%s by %s at %s", approval.Decision, approval.DecidedBy, formatTime(approval.DecidedAt))
+	if approval.Reason != "" {
+		formatted = fmt.Sprintf("%s: %s", formatted, approval.Reason)
+	}
+
+	return formatted
+}
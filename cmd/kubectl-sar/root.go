@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/cloudogu/k8s-support-archive-lib/client"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+	"github.com/cloudogu/k8s-support-archive-lib/rbac"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// globalOptions contains the flags shared by all sub commands.
+type globalOptions struct {
+	kubeconfig string
+	context    string
+	namespace  string
+	output     string
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &globalOptions{}
+
+	cmd := &cobra.Command{
+		Use:           "kubectl-sar",
+		Short:         "Manage SupportArchives of the Cloudogu EcoSystem",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.validate()
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&opts.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file to use")
+	flags.StringVar(&opts.context, "context", "", "The name of the kubeconfig context to use")
+	flags.StringVarP(&opts.namespace, "namespace", "n", "", "The namespace of the SupportArchives")
+	flags.StringVarP(&opts.output, "output", "o", outputTable, "Output format. One of: table, json, yaml")
+
+	cmd.AddCommand(
+		newCreateCmd(opts),
+		newListCmd(opts),
+		newDescribeCmd(opts),
+		newWaitCmd(opts),
+		newDownloadCmd(opts),
+		newDeleteCmd(opts),
+		newApproveCmd(opts),
+		newRejectCmd(opts),
+		newCancelCmd(opts),
+	)
+
+	return cmd
+}
+
+func (opts *globalOptions) validate() error {
+	switch opts.output {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q: must be one of table, json, yaml", opts.output)
+	}
+}
+
+// restConfig loads the kubeconfig and returns the config of the selected context and the selected namespace.
+func (opts *globalOptions) restConfig() (*rest.Config, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = opts.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: opts.context}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	namespace := opts.namespace
+	if namespace == "" {
+		namespace, _, err = clientConfig.Namespace()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to determine namespace: %w", err)
+		}
+	}
+
+	return restConfig, namespace, nil
+}
+
+// supportArchiveClient creates a support archive client and returns it with the selected namespace.
+func (opts *globalOptions) supportArchiveClient() (clientv1.SupportArchiveV1Interface, string, error) {
+	restConfig, namespace, err := opts.restConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	clientSet, err := client.NewSupportArchiveClientSet(restConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create support archive client: %w", err)
+	}
+
+	return clientSet.SupportArchiveV1(), namespace, nil
+}
+
+// supportArchives creates a client for the SupportArchives in the selected namespace.
+func (opts *globalOptions) supportArchives() (clientv1.SupportArchiveInterface, error) {
+	supportArchiveClient, namespace, err := opts.supportArchiveClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return supportArchiveClient.SupportArchives(namespace), nil
+}
+
+// checkAccess fails if the current user may not perform the verb on SupportArchives in the selected namespace.
+func (opts *globalOptions) checkAccess(ctx context.Context, verb string) error {
+	restConfig, namespace, err := opts.restConfig()
+	if err != nil {
+		return err
+	}
+
+	checker, err := rbac.NewAccessChecker(restConfig)
+	if err != nil {
+		return err
+	}
+
+	return checker.Check(ctx, namespace, verb, "")
+}
@@ -0,0 +1,631 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+	"github.com/cloudogu/k8s-support-archive-lib/integrity"
+	"github.com/cloudogu/k8s-support-archive-lib/rbac"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+    namespace: ecosystem
+current-context: test
+`
+
+func writeKubeconfig(t *testing.T, server string) string {
+	t.Helper()
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(fmt.Sprintf(testKubeconfig, server)), 0o600))
+	return kubeconfig
+}
+
+func writeJSON(t *testing.T, writer http.ResponseWriter, obj any) {
+	t.Helper()
+	writer.Header().Add("content-type", "application/json")
+	bytes, err := json.Marshal(obj)
+	require.NoError(t, err)
+	_, err = writer.Write(bytes)
+	require.NoError(t, err)
+}
+
+func execute(t *testing.T, server *httptest.Server, args ...string) (string, error) {
+	t.Helper()
+	cmd := newRootCmd()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetErr(io.Discard)
+	cmd.SetArgs(append([]string{"--kubeconfig", writeKubeconfig(t, server.URL)}, args...))
+
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+const accessReviewPath = "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews"
+
+// writeAccessReview answers a SelfSubjectAccessReview for creating SupportArchives in the namespace ecosystem.
+// The review is sent as protobuf, but the answer may be JSON.
+func writeAccessReview(t *testing.T, writer http.ResponseWriter, request *http.Request, allowed bool) {
+	t.Helper()
+	body, err := io.ReadAll(request.Body)
+	require.NoError(t, err)
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(body, nil, nil)
+	require.NoError(t, err)
+	review := obj.(*authorizationv1.SelfSubjectAccessReview)
+	assert.Equal(t, "ecosystem", review.Spec.ResourceAttributes.Namespace)
+	assert.Equal(t, "create", review.Spec.ResourceAttributes.Verb)
+	assert.Equal(t, "supportarchives", review.Spec.ResourceAttributes.Resource)
+
+	review.Status.Allowed = allowed
+	writeJSON(t, writer, review)
+}
+
+const selfSubjectReviewPath = "/apis/authentication.k8s.io/v1/selfsubjectreviews"
+
+// writeSelfSubjectReview answers a SelfSubjectReview with the given user.
+func writeSelfSubjectReview(t *testing.T, writer http.ResponseWriter, user string) {
+	t.Helper()
+	writeJSON(t, writer, &authenticationv1.SelfSubjectReview{
+		TypeMeta: metav1.TypeMeta{Kind: "SelfSubjectReview", APIVersion: authenticationv1.SchemeGroupVersion.String()},
+		Status:   authenticationv1.SelfSubjectReviewStatus{UserInfo: authenticationv1.UserInfo{Username: user}},
+	})
+}
+
+func testArchive() v1.SupportArchive {
+	return v1.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-archive", Namespace: "ecosystem"},
+		Status: v1.SupportArchiveStatus{
+			DownloadPath: "/support-archives/my-archive.zip",
+			Errors:       []string{"failed to collect logs"},
+			Conditions: []metav1.Condition{{
+				Type:    v1.ConditionSupportArchiveCreated,
+				Status:  metav1.ConditionTrue,
+				Reason:  "AllCollectorsExecuted",
+				Message: "created",
+			}},
+		},
+	}
+}
+
+func TestRootCmd(t *testing.T) {
+	t.Run("should fail for unsupported output format", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "list", "-o", "xml")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unsupported output format \"xml\"")
+	})
+}
+
+func TestCreateCmd(t *testing.T) {
+	t.Run("should create support archive with excluded contents, timeframe and ticket", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, http.MethodPost, request.Method)
+			if request.URL.Path == accessReviewPath {
+				writeAccessReview(t, writer, request, true)
+				return
+			}
+			assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/ecosystem/supportarchives", request.URL.Path)
+
+			archive := &v1.SupportArchive{}
+			require.NoError(t, json.NewDecoder(request.Body).Decode(archive))
+			assert.Equal(t, "my-archive", archive.Name)
+			assert.Equal(t, v1.ExcludedContents{SensitiveData: true, Logs: true}, archive.Spec.ExcludedContents)
+			assert.Equal(t, "2025-01-01T00:00:00Z", archive.Spec.ContentTimeframe.StartTime.UTC().Format(time.RFC3339))
+			assert.Equal(t, "2025-01-02T00:00:00Z", archive.Spec.ContentTimeframe.EndTime.UTC().Format(time.RFC3339))
+			assert.Equal(t, "SUP-1234", archive.Spec.Ticket)
+			assert.Equal(t, "operator crashes", archive.Spec.Reason)
+
+			writeJSON(t, writer, archive)
+		}))
+		defer server.Close()
+
+		// when
+		out, err := execute(t, server, "create", "my-archive", "--exclude-sensitive-data", "--exclude-logs",
+			"--start-time", "2025-01-01T00:00:00Z", "--end-time", "2025-01-02T00:00:00Z",
+			"--ticket", "SUP-1234", "--reason", "operator crashes")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "my-archive")
+	})
+	t.Run("should fail if creating is not allowed", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			require.Equal(t, accessReviewPath, request.URL.Path)
+			writeAccessReview(t, writer, request, false)
+		}))
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "create", "my-archive")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, rbac.ErrNotAllowed)
+		assert.ErrorContains(t, err, "cannot create SupportArchive my-archive: not allowed to create supportarchives in namespace ecosystem")
+	})
+	t.Run("should skip the access check", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			require.NotEqual(t, accessReviewPath, request.URL.Path)
+			archive := &v1.SupportArchive{}
+			require.NoError(t, json.NewDecoder(request.Body).Decode(archive))
+			writeJSON(t, writer, archive)
+		}))
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "create", "my-archive", "--skip-access-check")
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should require approval of another user", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.URL.Path == selfSubjectReviewPath {
+				writeSelfSubjectReview(t, writer, "alice")
+				return
+			}
+			archive := &v1.SupportArchive{}
+			require.NoError(t, json.NewDecoder(request.Body).Decode(archive))
+			assert.True(t, archive.Spec.RequiresApproval)
+			assert.Equal(t, "alice", archive.Spec.Requester)
+			writeJSON(t, writer, archive)
+		}))
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "create", "my-archive", "--require-approval", "--skip-access-check")
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail for start time after end time", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "create", "my-archive", "--start-time", "2025-01-02T00:00:00Z", "--end-time", "2025-01-01T00:00:00Z")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "must be before end time")
+	})
+}
+
+func Test_createOptions_supportArchive(t *testing.T) {
+	t.Run("should default to timeframe ending now", func(t *testing.T) {
+		// given
+		now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+		opts := &createOptions{since: 6 * time.Hour}
+
+		// when
+		archive, err := opts.supportArchive("my-archive", now)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, now, archive.Spec.ContentTimeframe.EndTime.Time)
+		assert.Equal(t, now.Add(-6*time.Hour), archive.Spec.ContentTimeframe.StartTime.Time)
+	})
+	t.Run("should fail for invalid time", func(t *testing.T) {
+		// given
+		opts := &createOptions{startTime: "yesterday"}
+
+		// when
+		_, err := opts.supportArchive("my-archive", time.Now())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid start time \"yesterday\"")
+	})
+}
+
+func TestListCmd(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, http.MethodGet, request.Method)
+		assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/ecosystem/supportarchives", request.URL.Path)
+
+		writeJSON(t, writer, v1.SupportArchiveList{Items: []v1.SupportArchive{testArchive()}})
+	}))
+	defer server.Close()
+
+	t.Run("should print table", func(t *testing.T) {
+		// when
+		out, err := execute(t, server, "list")
+
+		// then
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Len(t, lines, 2)
+		assert.Regexp(t, "^NAME +CREATED +DOWNLOAD PATH +ERRORS +AGE$", lines[0])
+		assert.Regexp(t, "^my-archive +True +/support-archives/my-archive.zip +1 +<unknown>$", lines[1])
+	})
+	t.Run("should print json", func(t *testing.T) {
+		// when
+		out, err := execute(t, server, "list", "-o", "json")
+
+		// then
+		require.NoError(t, err)
+		list := &v1.SupportArchiveList{}
+		require.NoError(t, json.Unmarshal([]byte(out), list))
+		assert.Equal(t, "SupportArchiveList", list.Kind)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "my-archive", list.Items[0].Name)
+	})
+	t.Run("should print yaml", func(t *testing.T) {
+		// when
+		out, err := execute(t, server, "list", "-o", "yaml")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "kind: SupportArchiveList")
+		assert.Contains(t, out, "name: my-archive")
+	})
+}
+
+func TestListCmd_filter(t *testing.T) {
+	other := testArchive()
+	other.Name = "other-archive"
+	other.Spec.Requester = "bob"
+	mine := testArchive()
+	mine.Spec.Requester = "alice"
+	mine.Spec.Ticket = "SUP-1234"
+
+	t.Run("should filter with field selector", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "spec.requester=alice,spec.ticket=SUP-1234", request.URL.Query().Get("fieldSelector"))
+
+			writeJSON(t, writer, v1.SupportArchiveList{Items: []v1.SupportArchive{mine}})
+		}))
+		defer server.Close()
+
+		// when
+		out, err := execute(t, server, "list", "--requester", "alice", "--ticket", "SUP-1234")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "my-archive")
+	})
+	t.Run("should filter on client if field selector is not supported", func(t *testing.T) {
+		// given
+		var fieldSelectors []string
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			fieldSelector := request.URL.Query().Get("fieldSelector")
+			fieldSelectors = append(fieldSelectors, fieldSelector)
+			if fieldSelector != "" {
+				writer.Header().Add("content-type", "application/json")
+				writer.WriteHeader(http.StatusBadRequest)
+				writeJSON(t, writer, metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonBadRequest, Code: http.StatusBadRequest,
+					Message: `field label not supported: spec.requester`})
+				return
+			}
+
+			writeJSON(t, writer, v1.SupportArchiveList{Items: []v1.SupportArchive{other, mine}})
+		}))
+		defer server.Close()
+
+		// when
+		out, err := execute(t, server, "list", "--requester", "alice")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"spec.requester=alice", ""}, fieldSelectors)
+		assert.Contains(t, out, "my-archive")
+		assert.NotContains(t, out, "other-archive")
+	})
+}
+
+func TestDescribeCmd(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/other/supportarchives/my-archive", request.URL.Path)
+
+		writeJSON(t, writer, testArchive())
+	}))
+	defer server.Close()
+
+	// when
+	out, err := execute(t, server, "describe", "my-archive", "-n", "other")
+
+	// then
+	require.NoError(t, err)
+	assert.Contains(t, out, "Name:")
+	assert.Contains(t, out, "Conditions:")
+	assert.Regexp(t, "Created +True +AllCollectorsExecuted", out)
+	assert.Contains(t, out, "  - failed to collect logs")
+}
+
+func TestWaitCmd(t *testing.T) {
+	t.Run("should wait for condition", func(t *testing.T) {
+		// given
+		requestCounter := 0
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			requestCounter++
+			archive := testArchive()
+			if requestCounter == 1 {
+				archive.Status.Conditions = nil
+			}
+			writeJSON(t, writer, archive)
+		}))
+		defer server.Close()
+
+		// when
+		out, err := execute(t, server, "wait", "my-archive", "--interval", "10ms")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, requestCounter)
+		assert.Contains(t, out, "my-archive")
+	})
+	t.Run("should fail on timeout", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writeJSON(t, writer, testArchive())
+		}))
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "wait", "my-archive", "--for", "NodeInfoFetched", "--interval", "10ms", "--timeout", "50ms")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to wait for condition NodeInfoFetched of SupportArchive my-archive")
+	})
+	t.Run("should fail right away for cancelled support archive", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			archive := testArchive()
+			archive.Status.Conditions = nil
+			archive.Status.Phase = v1.StatusPhaseCancelled
+			writeJSON(t, writer, archive)
+		}))
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "wait", "my-archive", "--interval", "10ms")
+
+		// then
+		require.ErrorIs(t, err, clientv1.ErrCancelled)
+		assert.EqualError(t, err, "failed to wait for condition Created of SupportArchive my-archive: the supportArchive was cancelled")
+	})
+}
+
+func TestDeleteCmd(t *testing.T) {
+	// given
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, http.MethodDelete, request.Method)
+		deleted = append(deleted, request.URL.Path)
+		if strings.HasSuffix(request.URL.Path, "/missing") {
+			writer.Header().Add("content-type", "application/json")
+			writer.WriteHeader(http.StatusNotFound)
+			writeJSON(t, writer, metav1.Status{Status: metav1.StatusFailure, Code: http.StatusNotFound, Reason: metav1.StatusReasonNotFound})
+			return
+		}
+		writeJSON(t, writer, metav1.Status{Status: metav1.StatusSuccess})
+	}))
+	defer server.Close()
+
+	// when
+	out, err := execute(t, server, "delete", "first", "missing")
+
+	// then
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "failed to delete SupportArchive missing")
+	assert.Len(t, deleted, 2)
+	assert.Contains(t, out, "supportarchive \"first\" deleted")
+}
+
+func TestCancelCmd(t *testing.T) {
+	t.Run("should cancel support archive", func(t *testing.T) {
+		// given
+		archive := testArchive()
+		archive.Status.Conditions = nil
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/ecosystem/supportarchives/my-archive", request.URL.Path)
+			if request.Method == http.MethodPatch {
+				body, err := io.ReadAll(request.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t, `{"spec":{"cancel":true}}`, string(body))
+				archive.Spec.Cancel = true
+				// the operator acknowledges the cancellation immediately
+				archive.Status.Conditions = []metav1.Condition{{Type: v1.ConditionCancelled, Status: metav1.ConditionTrue}}
+			}
+			writeJSON(t, writer, archive)
+		}))
+		defer server.Close()
+
+		// when
+		out, err := execute(t, server, "cancel", "my-archive")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "supportarchive \"my-archive\" cancelled\n", out)
+	})
+	t.Run("should fail for created support archive", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			assert.Equal(t, http.MethodGet, request.Method)
+			writeJSON(t, writer, testArchive())
+		}))
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "cancel", "my-archive")
+
+		// then
+		require.ErrorIs(t, err, clientv1.ErrAlreadyCreated)
+	})
+}
+
+func TestApproveCmd(t *testing.T) {
+	t.Run("should approve support archive of another user", func(t *testing.T) {
+		// given
+		var patched map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			archive := testArchive()
+			archive.Spec.Requester = "alice"
+			archive.Spec.RequiresApproval = true
+			switch request.Method {
+			case http.MethodPost:
+				require.Equal(t, selfSubjectReviewPath, request.URL.Path)
+				writeSelfSubjectReview(t, writer, "bob")
+			case http.MethodGet:
+				writeJSON(t, writer, archive)
+			case http.MethodPatch:
+				assert.Equal(t, "/apis/k8s.cloudogu.com/v1/namespaces/ecosystem/supportarchives/my-archive", request.URL.Path)
+				require.NoError(t, json.NewDecoder(request.Body).Decode(&patched))
+				writeJSON(t, writer, archive)
+			}
+		}))
+		defer server.Close()
+
+		// when
+		out, err := execute(t, server, "approve", "my-archive", "--reason", "ticket checked")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "supportarchive \"my-archive\" approved\n", out)
+		annotations := patched["metadata"].(map[string]any)["annotations"].(map[string]any)
+		assert.Equal(t, "Approved", annotations["k8s.cloudogu.com/approval-decision"])
+		assert.Equal(t, "bob", annotations["k8s.cloudogu.com/approval-decided-by"])
+		assert.Equal(t, "ticket checked", annotations["k8s.cloudogu.com/approval-reason"])
+	})
+	t.Run("should fail to approve own support archive", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			require.NotEqual(t, http.MethodPatch, request.Method)
+			if request.Method == http.MethodPost {
+				writeSelfSubjectReview(t, writer, "alice")
+				return
+			}
+			archive := testArchive()
+			archive.Spec.Requester = "alice"
+			archive.Spec.RequiresApproval = true
+			writeJSON(t, writer, archive)
+		}))
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "approve", "my-archive")
+
+		// then
+		require.ErrorIs(t, err, clientv1.ErrSelfApproval)
+	})
+}
+
+func TestDownloadCmd(t *testing.T) {
+	t.Run("should download to file named after download path", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.URL.Path == "/api/v1/namespaces/ecosystem/services/archives/proxy/support-archives/my-archive.zip" {
+				_, _ = writer.Write([]byte("archive"))
+				return
+			}
+			writeJSON(t, writer, testDownloadArchive(t, "archive"))
+		}))
+		defer server.Close()
+		t.Chdir(t.TempDir())
+
+		// when
+		_, err := execute(t, server, "download", "my-archive", "--service", "archives")
+
+		// then
+		require.NoError(t, err)
+		content, err := os.ReadFile("my-archive.zip")
+		require.NoError(t, err)
+		assert.Equal(t, "archive", string(content))
+	})
+	t.Run("should write to stdout", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if strings.HasPrefix(request.URL.Path, "/api/v1/") {
+				_, _ = writer.Write([]byte("archive"))
+				return
+			}
+			writeJSON(t, writer, testDownloadArchive(t, "archive"))
+		}))
+		defer server.Close()
+
+		// when
+		out, err := execute(t, server, "download", "my-archive", "--service", "archives", "-f", "-")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "archive", out)
+	})
+	t.Run("should fail without digest unless verification is skipped", func(t *testing.T) {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if strings.HasPrefix(request.URL.Path, "/api/v1/") {
+				_, _ = writer.Write([]byte("archive"))
+				return
+			}
+			writeJSON(t, writer, testArchive())
+		}))
+		defer server.Close()
+
+		// when
+		_, err := execute(t, server, "download", "my-archive", "--service", "archives", "-f", "-")
+		out, skipErr := execute(t, server, "download", "my-archive", "--service", "archives", "-f", "-", "--skip-verification")
+
+		// then
+		require.ErrorIs(t, err, integrity.ErrMissingDigest)
+		require.NoError(t, skipErr)
+		assert.Equal(t, "archive", out)
+	})
+}
+
+// testDownloadArchive returns testArchive with size and digest of the content.
+func testDownloadArchive(t *testing.T, content string) v1.SupportArchive {
+	archive := testArchive()
+	digest, size, err := integrity.Digest(strings.NewReader(content))
+	require.NoError(t, err)
+	archive.Status.Digest = digest
+	archive.Status.Size = size
+
+	return archive
+}
+
+func Test_defaultDownloadFile(t *testing.T) {
+	assert.Equal(t, "archive.zip", defaultDownloadFile("name", "/path/to/archive.zip?x=y"))
+	assert.Equal(t, "name.zip", defaultDownloadFile("name", ""))
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+func newWaitCmd(global *globalOptions) *cobra.Command {
+	opts := &clientv1.WaitOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "wait NAME",
+		Short: "Wait until a condition of a SupportArchive is true",
+		Long:  "Wait until a condition of a SupportArchive is true. Fails right away if the SupportArchive is cancelled or rejected.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			supportArchives, err := global.supportArchives()
+			if err != nil {
+				return err
+			}
+
+			archive, err := clientv1.Wait(cmd.Context(), func(ctx context.Context) (*v1.SupportArchive, error) {
+				archive, err := supportArchives.Get(ctx, args[0], metav1.GetOptions{})
+				if err != nil {
+					return nil, fmt.Errorf("failed to get SupportArchive %s: %w", args[0], err)
+				}
+				return archive, nil
+			}, *opts)
+			if err != nil {
+				return fmt.Errorf("failed to wait for condition %s of SupportArchive %s: %w", opts.Condition, args[0], err)
+			}
+
+			return printArchive(cmd.OutOrStdout(), global.output, archive)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Condition, "for", v1.ConditionSupportArchiveCreated, "The condition to wait for")
+	flags.DurationVar(&opts.Timeout, "timeout", 30*time.Minute, "The maximum time to wait")
+	flags.DurationVar(&opts.Interval, "interval", 5*time.Second, "The interval between checks")
+
+	return cmd
+}
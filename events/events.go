@@ -0,0 +1,117 @@
+// Package events records Kubernetes events about the lifecycle of SupportArchives, so that the operator and other
+// tools show the same events in `kubectl describe supportarchive`.
+package events
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// DefaultComponent is the source component of events recorded by the support archive operator.
+const DefaultComponent = "k8s-support-archive-operator"
+
+// Reason is the programmatic identifier of an event in CamelCase.
+type Reason string
+
+const (
+	// ReasonCollectionStarted is recorded when the collection of the archive contents starts.
+	ReasonCollectionStarted Reason = "CollectionStarted"
+	// ReasonCollectorFailed is recorded when a collector failed to collect its contents.
+	ReasonCollectorFailed Reason = "CollectorFailed"
+	// ReasonCreated is recorded when the archive is ready for download. It matches v1.ConditionSupportArchiveCreated.
+	ReasonCreated Reason = v1.ConditionSupportArchiveCreated
+	// ReasonCancelled is recorded when the creation was stopped because of Cancel. It matches v1.ConditionCancelled.
+	ReasonCancelled Reason = v1.ConditionCancelled
+	// ReasonVolumeInfoFetched matches v1.ConditionVolumeInfoFetched.
+	ReasonVolumeInfoFetched Reason = v1.ConditionVolumeInfoFetched
+	// ReasonNodeInfoFetched matches v1.ConditionNodeInfoFetched.
+	ReasonNodeInfoFetched Reason = v1.ConditionNodeInfoFetched
+	// ReasonSecretsFetched matches v1.ConditionSecretsFetched.
+	ReasonSecretsFetched Reason = v1.ConditionSecretsFetched
+)
+
+// Recorder records the lifecycle events of SupportArchives.
+type Recorder interface {
+	// CollectionStarted records that the collection of the archive contents started.
+	CollectionStarted(archive *v1.SupportArchive)
+	// CollectorFailed records a warning that the collector of the given category failed.
+	CollectorFailed(archive *v1.SupportArchive, category string, err error)
+	// ConditionChanged records the condition with the condition type as reason. Conditions with status false are
+	// recorded as warnings.
+	ConditionChanged(archive *v1.SupportArchive, condition metav1.Condition)
+	// Ready records that the archive is ready for download.
+	Ready(archive *v1.SupportArchive)
+}
+
+type recorder struct {
+	recorder record.EventRecorder
+}
+
+// NewRecorder creates a Recorder that records with the given event recorder. The scheme of the event recorder must
+// contain the supportArchive types, see NewEventRecorder.
+func NewRecorder(eventRecorder record.EventRecorder) Recorder {
+	return &recorder{recorder: eventRecorder}
+}
+
+// NewEventRecorder creates an event recorder that sends the events of the given component to the API server. Its
+// scheme contains the client-go and supportArchive types. The returned function stops sending events.
+func NewEventRecorder(clientSet kubernetes.Interface, component string) (record.EventRecorder, func(), error) {
+	s := runtime.NewScheme()
+	err := scheme.AddToScheme(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to add client-go types to scheme: %w", err)
+	}
+	err = v1.AddToScheme(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to add supportArchive types to scheme: %w", err)
+	}
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientSet.CoreV1().Events("")})
+
+	return broadcaster.NewRecorder(s, corev1.EventSource{Component: component}), broadcaster.Shutdown, nil
+}
+
+func (r *recorder) CollectionStarted(archive *v1.SupportArchive) {
+	r.event(archive, corev1.EventTypeNormal, ReasonCollectionStarted, "Started collecting the support archive contents")
+}
+
+func (r *recorder) CollectorFailed(archive *v1.SupportArchive, category string, err error) {
+	r.event(archive, corev1.EventTypeWarning, ReasonCollectorFailed, fmt.Sprintf("Failed to collect %s: %v", category, err))
+}
+
+func (r *recorder) ConditionChanged(archive *v1.SupportArchive, condition metav1.Condition) {
+	eventType := corev1.EventTypeNormal
+	if condition.Status == metav1.ConditionFalse {
+		eventType = corev1.EventTypeWarning
+	}
+
+	message := condition.Message
+	if message == "" {
+		message = fmt.Sprintf("Condition %s is %s", condition.Type, condition.Status)
+	}
+
+	r.event(archive, eventType, Reason(condition.Type), message)
+}
+
+func (r *recorder) Ready(archive *v1.SupportArchive) {
+	message := "The support archive is ready for download"
+	if archive.Status.DownloadPath != "" {
+		message = fmt.Sprintf("%s at %s", message, archive.Status.DownloadPath)
+	}
+
+	r.event(archive, corev1.EventTypeNormal, ReasonCreated, message)
+}
+
+func (r *recorder) event(archive *v1.SupportArchive, eventType string, reason Reason, message string) {
+	r.recorder.Event(archive, eventType, string(reason), message)
+}
@@ -0,0 +1,124 @@
+package events
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+func newArchive() *v1.SupportArchive {
+	return &v1.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-archive", Namespace: "ecosystem", UID: "1234"},
+		Status:     v1.SupportArchiveStatus{DownloadPath: "/download/my-archive.zip"},
+	}
+}
+
+func recorded(t *testing.T, fakeRecorder *record.FakeRecorder) string {
+	t.Helper()
+	select {
+	case event := <-fakeRecorder.Events:
+		return event
+	default:
+		require.Fail(t, "no event recorded")
+		return ""
+	}
+}
+
+func TestReasons(t *testing.T) {
+	t.Run("should match condition types", func(t *testing.T) {
+		assert.Equal(t, v1.ConditionSupportArchiveCreated, string(ReasonCreated))
+		assert.Equal(t, v1.ConditionVolumeInfoFetched, string(ReasonVolumeInfoFetched))
+		assert.Equal(t, v1.ConditionNodeInfoFetched, string(ReasonNodeInfoFetched))
+		assert.Equal(t, v1.ConditionSecretsFetched, string(ReasonSecretsFetched))
+	})
+}
+
+func Test_recorder(t *testing.T) {
+	t.Run("should record collection start", func(t *testing.T) {
+		// given
+		fakeRecorder := record.NewFakeRecorder(1)
+		sut := NewRecorder(fakeRecorder)
+
+		// when
+		sut.CollectionStarted(newArchive())
+
+		// then
+		assert.Equal(t, "Normal CollectionStarted Started collecting the support archive contents", recorded(t, fakeRecorder))
+	})
+	t.Run("should record failed collector as warning", func(t *testing.T) {
+		// given
+		fakeRecorder := record.NewFakeRecorder(1)
+		sut := NewRecorder(fakeRecorder)
+
+		// when
+		sut.CollectorFailed(newArchive(), "logs", errors.New("loki unavailable"))
+
+		// then
+		assert.Equal(t, "Warning CollectorFailed Failed to collect logs: loki unavailable", recorded(t, fakeRecorder))
+	})
+	t.Run("should record conditions with their type as reason", func(t *testing.T) {
+		// given
+		fakeRecorder := record.NewFakeRecorder(2)
+		sut := NewRecorder(fakeRecorder)
+
+		// when
+		sut.ConditionChanged(newArchive(), metav1.Condition{Type: v1.ConditionNodeInfoFetched, Status: metav1.ConditionTrue})
+		sut.ConditionChanged(newArchive(), metav1.Condition{Type: v1.ConditionSecretsFetched, Status: metav1.ConditionFalse, Message: "forbidden"})
+
+		// then
+		assert.Equal(t, "Normal NodeInfoFetched Condition NodeInfoFetched is True", recorded(t, fakeRecorder))
+		assert.Equal(t, "Warning SecretsFetched forbidden", recorded(t, fakeRecorder))
+	})
+	t.Run("should record ready archive with download path", func(t *testing.T) {
+		// given
+		fakeRecorder := record.NewFakeRecorder(1)
+		sut := NewRecorder(fakeRecorder)
+
+		// when
+		sut.Ready(newArchive())
+
+		// then
+		assert.Equal(t, "Normal Created The support archive is ready for download at /download/my-archive.zip", recorded(t, fakeRecorder))
+	})
+}
+
+func TestNewEventRecorder(t *testing.T) {
+	t.Run("should send events referencing the supportArchive", func(t *testing.T) {
+		// given
+		clientSet := fake.NewClientset()
+		eventRecorder, stop, err := NewEventRecorder(clientSet, DefaultComponent)
+		require.NoError(t, err)
+		defer stop()
+
+		// when
+		NewRecorder(eventRecorder).Ready(newArchive())
+
+		// then
+		var events *corev1.EventList
+		require.Eventually(t, func() bool {
+			events, err = clientSet.CoreV1().Events("ecosystem").List(t.Context(), metav1.ListOptions{})
+			return err == nil && len(events.Items) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		event := events.Items[0]
+		assert.Equal(t, string(ReasonCreated), event.Reason)
+		assert.Equal(t, corev1.EventTypeNormal, event.Type)
+		assert.Equal(t, DefaultComponent, event.Source.Component)
+		assert.Equal(t, corev1.ObjectReference{
+			Kind:       "SupportArchive",
+			APIVersion: v1.GroupVersion.String(),
+			Namespace:  "ecosystem",
+			Name:       "my-archive",
+			UID:        "1234",
+		}, event.InvolvedObject)
+	})
+}
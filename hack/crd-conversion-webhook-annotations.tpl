@@ -0,0 +1,3 @@
+    {{- if and .Values.conversionWebhook.enabled .Values.conversionWebhook.certManagerCertificate }}
+    cert-manager.io/inject-ca-from: {{ .Values.conversionWebhook.certManagerCertificate }}
+    {{- end }}
@@ -0,0 +1,16 @@
+  {{- if .Values.conversionWebhook.enabled }}
+  conversion:
+    strategy: Webhook
+    webhook:
+      clientConfig:
+        {{- with .Values.conversionWebhook.caBundle }}
+        caBundle: {{ . }}
+        {{- end }}
+        service:
+          name: {{ .Values.conversionWebhook.service.name }}
+          namespace: {{ .Values.conversionWebhook.service.namespace | default .Release.Namespace }}
+          path: {{ .Values.conversionWebhook.service.path }}
+          port: {{ .Values.conversionWebhook.service.port }}
+      conversionReviewVersions:
+        - v1
+  {{- end }}
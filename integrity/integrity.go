@@ -0,0 +1,216 @@
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const digestPrefix = "sha256:"
+
+var (
+	// ErrMissingDigest is returned if the status of a SupportArchive contains no digest to verify against.
+	ErrMissingDigest = errors.New("support archive status contains no digest")
+	// ErrSizeMismatch is returned if the size of an archive differs from the size in the status.
+	ErrSizeMismatch = errors.New("support archive size does not match")
+	// ErrDigestMismatch is returned if the digest of an archive differs from the digest in the status.
+	ErrDigestMismatch = errors.New("support archive digest does not match")
+	// ErrMissingSignature is returned if a public key is given but the status contains no signature.
+	ErrMissingSignature = errors.New("support archive status contains no signature")
+	// ErrInvalidSignature is returned if the signature in the status cannot be verified with the given public key.
+	ErrInvalidSignature = errors.New("support archive signature is invalid")
+)
+
+// Digester computes the size and SHA-256 digest of all data written to it.
+type Digester struct {
+	hash hash.Hash
+	size int64
+}
+
+// NewDigester creates a new Digester.
+func NewDigester() *Digester {
+	return &Digester{hash: sha256.New()}
+}
+
+// Write adds the given bytes to the digest.
+func (d *Digester) Write(p []byte) (int, error) {
+	n, err := d.hash.Write(p)
+	d.size += int64(n)
+	return n, err
+}
+
+// Size returns the number of bytes written so far.
+func (d *Digester) Size() int64 {
+	return d.size
+}
+
+// Digest returns the digest of the bytes written so far in the form `sha256:<hex>`.
+func (d *Digester) Digest() string {
+	return digestPrefix + hex.EncodeToString(d.hash.Sum(nil))
+}
+
+// Check compares size and digest of the bytes written so far with the given status.
+func (d *Digester) Check(status v1.SupportArchiveStatus) error {
+	if status.Digest == "" {
+		return ErrMissingDigest
+	}
+	if status.Size != 0 && status.Size != d.Size() {
+		return fmt.Errorf("%w: expected %d bytes but got %d bytes", ErrSizeMismatch, status.Size, d.Size())
+	}
+	if !strings.EqualFold(status.Digest, d.Digest()) {
+		return fmt.Errorf("%w: expected %s but got %s", ErrDigestMismatch, status.Digest, d.Digest())
+	}
+
+	return nil
+}
+
+// Digest reads the archive from the given reader and returns its digest in the form `sha256:<hex>` and its size.
+func Digest(reader io.Reader) (digest string, size int64, err error) {
+	digester := NewDigester()
+	_, err = io.Copy(digester, reader)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read support archive: %w", err)
+	}
+
+	return digester.Digest(), digester.Size(), nil
+}
+
+// Sign creates a detached ed25519 signature over the given digest.
+// The signature covers the raw SHA-256 sum, not its textual representation.
+func Sign(privateKey ed25519.PrivateKey, digest string) (*v1.ArchiveSignature, error) {
+	sum, err := decodeDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.ArchiveSignature{
+		Algorithm: v1.SignatureAlgorithmEd25519,
+		Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, sum)),
+	}, nil
+}
+
+type verifyOptions struct {
+	publicKey ed25519.PublicKey
+}
+
+// VerifyOption configures Verify.
+type VerifyOption func(*verifyOptions)
+
+// WithPublicKey makes Verify additionally check the signature of the archive with the given key.
+func WithPublicKey(publicKey ed25519.PublicKey) VerifyOption {
+	return func(opts *verifyOptions) {
+		opts.publicKey = publicKey
+	}
+}
+
+// Verify reads the archive from the given reader and checks that it matches the size and digest in the given status.
+// If a public key is given, the signature in the status is verified as well.
+func Verify(reader io.Reader, status v1.SupportArchiveStatus, opts ...VerifyOption) error {
+	digester := NewDigester()
+	_, err := io.Copy(digester, reader)
+	if err != nil {
+		return fmt.Errorf("failed to read support archive: %w", err)
+	}
+
+	err = digester.Check(status)
+	if err != nil {
+		return err
+	}
+
+	options := &verifyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.publicKey == nil {
+		return nil
+	}
+
+	return VerifySignature(options.publicKey, status)
+}
+
+// VerifySignature checks the signature in the given status against its digest.
+func VerifySignature(publicKey ed25519.PublicKey, status v1.SupportArchiveStatus) error {
+	if status.Signature == nil {
+		return ErrMissingSignature
+	}
+	if status.Signature.Algorithm != v1.SignatureAlgorithmEd25519 {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, status.Signature.Algorithm)
+	}
+
+	sum, err := decodeDigest(status.Digest)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(status.Signature.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	if !ed25519.Verify(publicKey, sum, signature) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS #8 ed25519 private key as stored in a signing key Secret.
+func ParsePrivateKey(pemBytes []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block of private key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is of type %T but ed25519 is required", key)
+	}
+
+	return privateKey, nil
+}
+
+// ParsePublicKey parses a PEM encoded PKIX ed25519 public key.
+func ParsePublicKey(pemBytes []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block of public key")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is of type %T but ed25519 is required", key)
+	}
+
+	return publicKey, nil
+}
+
+func decodeDigest(digest string) ([]byte, error) {
+	if !strings.HasPrefix(digest, digestPrefix) {
+		return nil, fmt.Errorf("digest %q is not a sha256 digest", digest)
+	}
+
+	sum, err := hex.DecodeString(strings.TrimPrefix(digest, digestPrefix))
+	if err != nil || len(sum) != sha256.Size {
+		return nil, fmt.Errorf("digest %q is not a valid sha256 digest", digest)
+	}
+
+	return sum, nil
+}
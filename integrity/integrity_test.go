@@ -0,0 +1,170 @@
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const (
+	testArchive       = "archive content"
+	testArchiveDigest = "sha256:c4e5f3dff4dcd9ffde31d20a0e35f8b3c8bd89a9c4b1f28f2fbe4e1fc64d3e0f"
+)
+
+func TestDigest(t *testing.T) {
+	// when
+	digest, size, err := Digest(strings.NewReader(testArchive))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(testArchive)), size)
+	assert.Regexp(t, "^sha256:[a-f0-9]{64}$", digest)
+}
+
+func TestVerify(t *testing.T) {
+	digest, size, err := Digest(strings.NewReader(testArchive))
+	require.NoError(t, err)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signature, err := Sign(privateKey, digest)
+	require.NoError(t, err)
+
+	t.Run("should verify size and digest", func(t *testing.T) {
+		// given
+		status := v1.SupportArchiveStatus{Size: size, Digest: digest}
+
+		// when
+		err := Verify(strings.NewReader(testArchive), status)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should verify signature", func(t *testing.T) {
+		// given
+		status := v1.SupportArchiveStatus{Size: size, Digest: digest, Signature: signature}
+
+		// when
+		err := Verify(strings.NewReader(testArchive), status, WithPublicKey(publicKey))
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail without digest", func(t *testing.T) {
+		// when
+		err := Verify(strings.NewReader(testArchive), v1.SupportArchiveStatus{})
+
+		// then
+		require.ErrorIs(t, err, ErrMissingDigest)
+	})
+	t.Run("should fail on size mismatch", func(t *testing.T) {
+		// given
+		status := v1.SupportArchiveStatus{Size: size + 1, Digest: digest}
+
+		// when
+		err := Verify(strings.NewReader(testArchive), status)
+
+		// then
+		require.ErrorIs(t, err, ErrSizeMismatch)
+	})
+	t.Run("should fail on digest mismatch", func(t *testing.T) {
+		// given
+		status := v1.SupportArchiveStatus{Digest: testArchiveDigest}
+
+		// when
+		err := Verify(strings.NewReader("tampered"), status)
+
+		// then
+		require.ErrorIs(t, err, ErrDigestMismatch)
+	})
+	t.Run("should fail if signature is missing", func(t *testing.T) {
+		// given
+		status := v1.SupportArchiveStatus{Size: size, Digest: digest}
+
+		// when
+		err := Verify(strings.NewReader(testArchive), status, WithPublicKey(publicKey))
+
+		// then
+		require.ErrorIs(t, err, ErrMissingSignature)
+	})
+	t.Run("should fail for signature of other key", func(t *testing.T) {
+		// given
+		otherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		status := v1.SupportArchiveStatus{Size: size, Digest: digest, Signature: signature}
+
+		// when
+		err = Verify(strings.NewReader(testArchive), status, WithPublicKey(otherPublicKey))
+
+		// then
+		require.ErrorIs(t, err, ErrInvalidSignature)
+	})
+}
+
+func TestSign(t *testing.T) {
+	t.Run("should fail for invalid digest", func(t *testing.T) {
+		// given
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		// when
+		_, err = Sign(privateKey, "md5:abc")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "is not a sha256 digest")
+	})
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Run("should parse PEM encoded ed25519 keys", func(t *testing.T) {
+		// given
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		require.NoError(t, err)
+		publicBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+		require.NoError(t, err)
+
+		// when
+		parsedPrivateKey, privateErr := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}))
+		parsedPublicKey, publicErr := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}))
+
+		// then
+		require.NoError(t, privateErr)
+		require.NoError(t, publicErr)
+		assert.Equal(t, privateKey, parsedPrivateKey)
+		assert.Equal(t, publicKey, parsedPublicKey)
+	})
+	t.Run("should fail for non-PEM data", func(t *testing.T) {
+		// when
+		_, err := ParsePrivateKey([]byte("no pem"))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to decode PEM block of private key")
+	})
+	t.Run("should fail for non-ed25519 key", func(t *testing.T) {
+		// given
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+		require.NoError(t, err)
+		privateBytes, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+		require.NoError(t, err)
+
+		// when
+		_, err = ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "but ed25519 is required")
+	})
+}
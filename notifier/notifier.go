@@ -0,0 +1,185 @@
+// Package notifier sends the notifications configured in the spec of SupportArchives to HTTP webhooks.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+// maxErrorBodySize limits how much of an error response is included in a StatusError.
+const maxErrorBodySize = 512
+
+// DefaultTimeout limits every request of the default HTTP client, so that a webhook that does not answer cannot block
+// the notifier.
+const DefaultTimeout = 10 * time.Second
+
+// StatusError is returned if a webhook answers with a status code other than 2xx.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Body is the beginning of the response body.
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook answered with status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("webhook answered with status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsRetryable returns true if sending a notification failed because of the network, throttling or a server error.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return true
+}
+
+// DefaultRetryPolicy returns the policy used if no other policy is configured. It tries every webhook up to five
+// times and retries errors for which IsRetryable returns true.
+func DefaultRetryPolicy() clientv1.RetryPolicy {
+	return clientv1.RetryPolicy{
+		Backoff: wait.Backoff{
+			Duration: time.Second,
+			Factor:   2,
+			Steps:    5,
+			Cap:      30 * time.Second,
+		},
+		MaxAttempts: 5,
+		Retryable:   IsRetryable,
+	}
+}
+
+// Notifier sends notifications about SupportArchives.
+type Notifier interface {
+	// Notify sends the event to every webhook of the archive that is configured for it. All webhooks are notified even
+	// if some of them fail. The errors of the failed webhooks are joined.
+	Notify(ctx context.Context, archive *v1.SupportArchive, event v1.NotificationEvent, message string) error
+}
+
+// Option configures the Notifier.
+type Option func(n *notifier)
+
+// WithHTTPClient replaces the HTTP client with the DefaultTimeout the notifier sends requests with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *notifier) {
+		n.httpClient = client
+	}
+}
+
+// WithRetryPolicy replaces the DefaultRetryPolicy of the notifier.
+func WithRetryPolicy(policy clientv1.RetryPolicy) Option {
+	return func(n *notifier) {
+		n.retryPolicy = policy
+	}
+}
+
+type notifier struct {
+	secrets     SecretReader
+	httpClient  *http.Client
+	retryPolicy clientv1.RetryPolicy
+}
+
+// NewNotifier creates a Notifier that reads the header values of webhooks with the given SecretReader.
+func NewNotifier(secrets SecretReader, opts ...Option) Notifier {
+	n := &notifier{
+		secrets:     secrets,
+		httpClient:  &http.Client{Timeout: DefaultTimeout},
+		retryPolicy: DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	return n
+}
+
+func (n *notifier) Notify(ctx context.Context, archive *v1.SupportArchive, event v1.NotificationEvent, message string) error {
+	if archive.Spec.Notifications == nil {
+		return nil
+	}
+
+	payload := Payload{
+		Event:        event,
+		Namespace:    archive.Namespace,
+		Name:         archive.Name,
+		Message:      message,
+		DownloadPath: archive.Status.DownloadPath,
+	}
+
+	var errs []error
+	for _, webhook := range archive.Spec.Notifications.Webhooks {
+		if !slices.Contains(webhook.Events, event) {
+			continue
+		}
+
+		err := n.notifyWebhook(ctx, archive.Namespace, webhook, payload)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to notify webhook %s of supportArchive %s/%s: %w", webhook.Name, archive.Namespace, archive.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (n *notifier) notifyWebhook(ctx context.Context, namespace string, webhook v1.WebhookNotification, payload Payload) error {
+	body, err := render(webhook, payload)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for _, webhookHeader := range webhook.Headers {
+		value, err := n.secrets.ReadSecretKey(ctx, namespace, webhookHeader.SecretKeyRef)
+		if err != nil {
+			return fmt.Errorf("failed to read value of header %s: %w", webhookHeader.Name, err)
+		}
+		request.Header.Set(webhookHeader.Name, value)
+	}
+
+	return n.retryPolicy.Do(ctx, func(ctx context.Context, _ int) error {
+		return n.send(request.Clone(ctx), body)
+	})
+}
+
+func (n *notifier) send(request *http.Request, body []byte) error {
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	request.ContentLength = int64(len(body))
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		responseBody, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+		return &StatusError{StatusCode: response.StatusCode, Body: string(bytes.TrimSpace(responseBody))}
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+
+	return nil
+}
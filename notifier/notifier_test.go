@@ -0,0 +1,268 @@
+package notifier
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+)
+
+var testCtx = context.Background()
+
+type receivedRequest struct {
+	header http.Header
+	body   string
+}
+
+// newWebhookServer answers requests with the given status codes in order and with 200 OK afterward.
+func newWebhookServer(t *testing.T, statusCodes ...int) (*httptest.Server, func() []receivedRequest) {
+	var mutex sync.Mutex
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		received = append(received, receivedRequest{header: request.Header, body: string(body)})
+		statusCode := http.StatusOK
+		if len(received) <= len(statusCodes) {
+			statusCode = statusCodes[len(received)-1]
+		}
+		writer.WriteHeader(statusCode)
+		_, _ = writer.Write([]byte("answer"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedRequest {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return received
+	}
+}
+
+func fastRetries() clientv1.RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.Backoff = wait.Backoff{Duration: time.Millisecond, Steps: 5}
+
+	return policy
+}
+
+func archiveWithWebhooks(webhooks ...v1.WebhookNotification) *v1.SupportArchive {
+	return &v1.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-archive", Namespace: "ecosystem"},
+		Spec:       v1.SupportArchiveSpec{Notifications: &v1.Notifications{Webhooks: webhooks}},
+		Status:     v1.SupportArchiveStatus{DownloadPath: "/download/my-archive.zip"},
+	}
+}
+
+func newSecretReader() SecretReader {
+	return NewSecretReader(fake.NewClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: "ecosystem", Labels: map[string]string{LabelNotificationSecret: "true"}},
+		Data:       map[string][]byte{"token": []byte("Bearer secret")},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "ecosystem"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}).CoreV1())
+}
+
+func Test_notifier_Notify(t *testing.T) {
+	t.Run("should send configured events with secret headers", func(t *testing.T) {
+		// given
+		server, received := newWebhookServer(t)
+		archive := archiveWithWebhooks(v1.WebhookNotification{
+			Name:    "on-call",
+			URL:     server.URL,
+			Events:  []v1.NotificationEvent{v1.NotificationEventCreated},
+			Headers: []v1.WebhookHeader{{Name: "Authorization", SecretKeyRef: v1.SecretKeyReference{Name: "webhook", Key: "token"}}},
+		})
+		sut := NewNotifier(newSecretReader())
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCreated, "ready")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, received(), 1)
+		assert.Equal(t, "Bearer secret", received()[0].header.Get("Authorization"))
+		assert.Equal(t, "application/json", received()[0].header.Get("Content-Type"))
+		assert.JSONEq(t, `{"event":"Created","namespace":"ecosystem","name":"my-archive","message":"ready","downloadPath":"/download/my-archive.zip"}`, received()[0].body)
+	})
+	t.Run("should skip webhooks not configured for the event", func(t *testing.T) {
+		// given
+		server, received := newWebhookServer(t)
+		archive := archiveWithWebhooks(v1.WebhookNotification{Name: "on-call", URL: server.URL, Events: []v1.NotificationEvent{v1.NotificationEventCreated}})
+		sut := NewNotifier(newSecretReader())
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCollectionStarted, "started")
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, received())
+	})
+	t.Run("should do nothing without notifications", func(t *testing.T) {
+		// when
+		err := NewNotifier(newSecretReader()).Notify(testCtx, &v1.SupportArchive{}, v1.NotificationEventCreated, "")
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should retry server errors", func(t *testing.T) {
+		// given
+		server, received := newWebhookServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+		archive := archiveWithWebhooks(v1.WebhookNotification{Name: "on-call", URL: server.URL, Events: []v1.NotificationEvent{v1.NotificationEventCreated}})
+		sut := NewNotifier(newSecretReader(), WithRetryPolicy(fastRetries()))
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCreated, "ready")
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, received(), 3)
+	})
+	t.Run("should not retry client errors and notify the other webhooks", func(t *testing.T) {
+		// given
+		failing, failingReceived := newWebhookServer(t, http.StatusBadRequest)
+		working, workingReceived := newWebhookServer(t)
+		archive := archiveWithWebhooks(
+			v1.WebhookNotification{Name: "failing", URL: failing.URL, Events: []v1.NotificationEvent{v1.NotificationEventCollectorFailed}},
+			v1.WebhookNotification{Name: "working", URL: working.URL, Events: []v1.NotificationEvent{v1.NotificationEventCollectorFailed}},
+		)
+		sut := NewNotifier(newSecretReader(), WithRetryPolicy(fastRetries()))
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCollectorFailed, "logs failed")
+
+		// then
+		require.Error(t, err)
+		var statusErr *StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+		assert.EqualError(t, err, "failed to notify webhook failing of supportArchive ecosystem/my-archive: webhook answered with status 400: answer")
+		assert.Len(t, failingReceived(), 1)
+		assert.Len(t, workingReceived(), 1)
+	})
+	t.Run("should give up after the maximum number of attempts", func(t *testing.T) {
+		// given
+		server, received := newWebhookServer(t, 500, 500, 500, 500, 500, 500)
+		archive := archiveWithWebhooks(v1.WebhookNotification{Name: "on-call", URL: server.URL, Events: []v1.NotificationEvent{v1.NotificationEventCreated}})
+		sut := NewNotifier(newSecretReader(), WithRetryPolicy(fastRetries()))
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCreated, "ready")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "the maximum number of 5 attempts was reached")
+		assert.Len(t, received(), 5)
+	})
+	t.Run("should fail without sending if the secret key is missing", func(t *testing.T) {
+		// given
+		server, received := newWebhookServer(t)
+		archive := archiveWithWebhooks(v1.WebhookNotification{
+			Name:    "on-call",
+			URL:     server.URL,
+			Events:  []v1.NotificationEvent{v1.NotificationEventCreated},
+			Headers: []v1.WebhookHeader{{Name: "Authorization", SecretKeyRef: v1.SecretKeyReference{Name: "webhook", Key: "password"}}},
+		})
+		sut := NewNotifier(newSecretReader())
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCreated, "ready")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to read value of header Authorization: secret ecosystem/webhook has no key password")
+		assert.Empty(t, received())
+	})
+	t.Run("should fail if the secret does not exist", func(t *testing.T) {
+		// given
+		archive := archiveWithWebhooks(v1.WebhookNotification{
+			Name:    "on-call",
+			URL:     "http://localhost",
+			Events:  []v1.NotificationEvent{v1.NotificationEventCreated},
+			Headers: []v1.WebhookHeader{{Name: "Authorization", SecretKeyRef: v1.SecretKeyReference{Name: "other", Key: "token"}}},
+		})
+		sut := NewNotifier(newSecretReader())
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCreated, "ready")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to get secret ecosystem/other")
+	})
+	t.Run("should fail without sending if the secret is not labeled for notifications", func(t *testing.T) {
+		// given
+		server, received := newWebhookServer(t)
+		archive := archiveWithWebhooks(v1.WebhookNotification{
+			Name:    "on-call",
+			URL:     server.URL,
+			Events:  []v1.NotificationEvent{v1.NotificationEventCreated},
+			Headers: []v1.WebhookHeader{{Name: "Authorization", SecretKeyRef: v1.SecretKeyReference{Name: "database", Key: "password"}}},
+		})
+		sut := NewNotifier(newSecretReader())
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCreated, "ready")
+
+		// then
+		require.ErrorIs(t, err, ErrSecretNotAllowed)
+		assert.ErrorContains(t, err, "secret ecosystem/database has no label k8s.cloudogu.com/support-archive-notification=true")
+		assert.Empty(t, received())
+	})
+	t.Run("should use http client with timeout by default", func(t *testing.T) {
+		// when
+		sut := NewNotifier(newSecretReader())
+
+		// then
+		assert.Equal(t, DefaultTimeout, sut.(*notifier).httpClient.Timeout)
+	})
+	t.Run("should use the given http client", func(t *testing.T) {
+		// given
+		server, received := newWebhookServer(t)
+		archive := archiveWithWebhooks(v1.WebhookNotification{Name: "on-call", URL: server.URL, Events: []v1.NotificationEvent{v1.NotificationEventCreated}})
+		sut := NewNotifier(newSecretReader(), WithHTTPClient(server.Client()))
+
+		// when
+		err := sut.Notify(testCtx, archive, v1.NotificationEventCreated, "ready")
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, received(), 1)
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "network error", err: io.ErrUnexpectedEOF, want: true},
+		{name: "server error", err: &StatusError{StatusCode: http.StatusBadGateway}, want: true},
+		{name: "throttling", err: &StatusError{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "client error", err: &StatusError{StatusCode: http.StatusUnauthorized}, want: false},
+		{name: "cancelled", err: context.Canceled, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// Payload contains the data of a notification. Generic webhooks receive it as JSON object and payload templates are
+// rendered with it.
+type Payload struct {
+	Event        v1.NotificationEvent `json:"event"`
+	Namespace    string               `json:"namespace"`
+	Name         string               `json:"name"`
+	Message      string               `json:"message,omitempty"`
+	DownloadPath string               `json:"downloadPath,omitempty"`
+}
+
+// Text describes the notification in a single line for chat messages.
+func (p Payload) Text() string {
+	text := fmt.Sprintf("SupportArchive %s/%s: %s", p.Namespace, p.Name, p.Event)
+	if p.Message != "" {
+		text = fmt.Sprintf("%s - %s", text, p.Message)
+	}
+	if p.DownloadPath != "" && p.Event == v1.NotificationEventCreated {
+		text = fmt.Sprintf("%s (download: %s)", text, p.DownloadPath)
+	}
+
+	return text
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+type teamsMessage struct {
+	Type    string `json:"@type"`
+	Context string `json:"@context"`
+	Summary string `json:"summary"`
+	Text    string `json:"text"`
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+}
+
+// render creates the request body of the webhook from its payload template or its format.
+func render(webhook v1.WebhookNotification, payload Payload) ([]byte, error) {
+	if strings.TrimSpace(webhook.PayloadTemplate) != "" {
+		return renderTemplate(webhook.PayloadTemplate, payload)
+	}
+
+	var message any
+	switch webhook.Format {
+	case v1.WebhookFormatGeneric, "":
+		message = payload
+	case v1.WebhookFormatSlack:
+		message = slackMessage{Text: payload.Text()}
+	case v1.WebhookFormatTeams:
+		message = teamsMessage{
+			Type:    "MessageCard",
+			Context: "https://schema.org/extensions",
+			Summary: fmt.Sprintf("SupportArchive %s/%s", payload.Namespace, payload.Name),
+			Text:    payload.Text(),
+		}
+	default:
+		return nil, fmt.Errorf("unsupported webhook format %q", webhook.Format)
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	return body, nil
+}
+
+func renderTemplate(text string, payload Payload) ([]byte, error) {
+	tmpl, err := template.New("payload").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payload template: %w", err)
+	}
+
+	body := &bytes.Buffer{}
+	err = tmpl.Execute(body, payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render payload template: %w", err)
+	}
+	if !json.Valid(body.Bytes()) {
+		return nil, fmt.Errorf("payload template did not render valid JSON: %s", body.String())
+	}
+
+	return body.Bytes(), nil
+}
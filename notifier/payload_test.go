@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var testPayload = Payload{
+	Event:        v1.NotificationEventCreated,
+	Namespace:    "ecosystem",
+	Name:         "my-archive",
+	Message:      `ready "now"`,
+	DownloadPath: "/download/my-archive.zip",
+}
+
+func Test_render(t *testing.T) {
+	t.Run("should render slack message", func(t *testing.T) {
+		// when
+		body, err := render(v1.WebhookNotification{Format: v1.WebhookFormatSlack}, testPayload)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"text":"SupportArchive ecosystem/my-archive: Created - ready \"now\" (download: /download/my-archive.zip)"}`, string(body))
+	})
+	t.Run("should render teams message", func(t *testing.T) {
+		// when
+		body, err := render(v1.WebhookNotification{Format: v1.WebhookFormatTeams}, testPayload)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"@type": "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary": "SupportArchive ecosystem/my-archive",
+			"text": "SupportArchive ecosystem/my-archive: Created - ready \"now\" (download: /download/my-archive.zip)"
+		}`, string(body))
+	})
+	t.Run("should prefer the payload template over the format", func(t *testing.T) {
+		// given
+		webhook := v1.WebhookNotification{
+			Format:          v1.WebhookFormatSlack,
+			PayloadTemplate: `{"summary": {{ json .Message }}, "archive": "{{ .Namespace }}/{{ .Name }}"}`,
+		}
+
+		// when
+		body, err := render(webhook, testPayload)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"summary":"ready \"now\"","archive":"ecosystem/my-archive"}`, string(body))
+	})
+	t.Run("should fail for invalid template", func(t *testing.T) {
+		// when
+		_, err := render(v1.WebhookNotification{PayloadTemplate: `{{ .Unknown }}`}, testPayload)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to render payload template")
+	})
+	t.Run("should fail if the template does not render JSON", func(t *testing.T) {
+		// when
+		_, err := render(v1.WebhookNotification{PayloadTemplate: `text {{ .Name }}`}, testPayload)
+
+		// then
+		require.Error(t, err)
+		assert.EqualError(t, err, "payload template did not render valid JSON: text my-archive")
+	})
+	t.Run("should fail for unsupported format", func(t *testing.T) {
+		// when
+		_, err := render(v1.WebhookNotification{Format: "Mail"}, testPayload)
+
+		// then
+		require.Error(t, err)
+		assert.EqualError(t, err, `unsupported webhook format "Mail"`)
+	})
+}
@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// LabelNotificationSecret marks Secrets that notifications may read header values from. It must be set to "true".
+// Other Secrets cannot be referenced, as everyone who creates SupportArchives could send them to any URL otherwise.
+const LabelNotificationSecret = "k8s.cloudogu.com/support-archive-notification"
+
+// ErrSecretNotAllowed is returned if a referenced Secret does not have the label LabelNotificationSecret.
+var ErrSecretNotAllowed = errors.New("secret is not allowed for notifications")
+
+// SecretReader reads values referenced by SupportArchives from Secrets.
+type SecretReader interface {
+	// ReadSecretKey returns the value of the referenced key of a Secret in the namespace.
+	ReadSecretKey(ctx context.Context, namespace string, ref v1.SecretKeyReference) (string, error)
+}
+
+type secretReader struct {
+	secrets corev1client.SecretsGetter
+}
+
+// NewSecretReader creates a SecretReader that gets Secrets with the given client. It only reads Secrets with the label
+// LabelNotificationSecret.
+func NewSecretReader(secrets corev1client.SecretsGetter) SecretReader {
+	return &secretReader{secrets: secrets}
+}
+
+func (r *secretReader) ReadSecretKey(ctx context.Context, namespace string, ref v1.SecretKeyReference) (string, error) {
+	secret, err := r.secrets.Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %w", namespace, ref.Name, err)
+	}
+	if secret.Labels[LabelNotificationSecret] != "true" {
+		return "", fmt.Errorf("%w: secret %s/%s has no label %s=true", ErrSecretNotAllowed, namespace, ref.Name, LabelNotificationSecret)
+	}
+
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %s", namespace, ref.Name, ref.Key)
+	}
+
+	return string(value), nil
+}
@@ -0,0 +1,152 @@
+// Package policy evaluates SupportArchives against the cluster-wide SupportArchivePolicies, so that admission webhooks
+// and the support archive operator enforce the same rules.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// ErrViolation is matched by every Violation returned by Evaluate.
+var ErrViolation = errors.New("supportArchive violates policy")
+
+// Violation describes a rule of a SupportArchivePolicy that a SupportArchive does not comply with.
+type Violation struct {
+	// Policy is the name of the violated SupportArchivePolicy.
+	Policy string
+	// Message describes the violated rule.
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("supportArchivePolicy %s: %s", v.Policy, v.Message)
+}
+
+// Is lets errors.Is match the Violation with ErrViolation.
+func (v *Violation) Is(target error) bool {
+	return target == ErrViolation
+}
+
+// Evaluate checks the supportArchive against the limits and forbidden contents of all policies and joins all
+// violations. namespaceArchives are the SupportArchives in the namespace of the supportArchive, e.g. from a cache.
+// Only those in progress count for MaxConcurrentPerNamespace, the supportArchive itself never does, so the operator
+// can evaluate existing SupportArchives like webhooks evaluate new ones.
+func Evaluate(policies []v1.SupportArchivePolicy, supportArchive *v1.SupportArchive, namespaceArchives []v1.SupportArchive) error {
+	var errs []error
+	for _, policy := range sortedByName(policies) {
+		spec := policy.Spec
+		for _, category := range spec.ForbiddenContents {
+			excluded, known := excludes(supportArchive.Spec.ExcludedContents, category)
+			if !known {
+				// a policy for another version of the library must not be ignored
+				errs = append(errs, &Violation{Policy: policy.Name, Message: fmt.Sprintf("unknown content category %s", category)})
+			} else if !excluded {
+				errs = append(errs, &Violation{Policy: policy.Name, Message: fmt.Sprintf("content %s must be excluded", category)})
+			}
+		}
+
+		if spec.Limits == nil {
+			continue
+		}
+		if spec.Limits.MaxTimeframe != nil {
+			timeframe := supportArchive.Spec.ContentTimeframe
+			length := timeframe.EndTime.Sub(timeframe.StartTime.Time)
+			if length > spec.Limits.MaxTimeframe.Duration {
+				errs = append(errs, &Violation{Policy: policy.Name, Message: fmt.Sprintf("content timeframe of %s exceeds the maximum of %s", length, spec.Limits.MaxTimeframe.Duration)})
+			}
+		}
+		if spec.Limits.MaxConcurrentPerNamespace != nil {
+			inProgress := countInProgress(supportArchive, namespaceArchives)
+			if inProgress >= int(*spec.Limits.MaxConcurrentPerNamespace) {
+				errs = append(errs, &Violation{Policy: policy.Name, Message: fmt.Sprintf("namespace %s already has %d supportArchives in progress, the maximum is %d", supportArchive.Namespace, inProgress, *spec.Limits.MaxConcurrentPerNamespace)})
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ApplyDefaults sets the defaults of the policies on a new supportArchive. Each field is taken from the policy with
+// the alphabetically first name that sets it. A timeframe is only set if the supportArchive has none, it ends now.
+func ApplyDefaults(policies []v1.SupportArchivePolicy, supportArchive *v1.SupportArchive, now time.Time) {
+	timeframeSet := !supportArchive.Spec.ContentTimeframe.StartTime.IsZero() || !supportArchive.Spec.ContentTimeframe.EndTime.IsZero()
+	for _, policy := range sortedByName(policies) {
+		defaults := policy.Spec.Defaults
+		if defaults == nil {
+			continue
+		}
+		if !timeframeSet && defaults.Timeframe != nil {
+			supportArchive.Spec.ContentTimeframe = v1.ContentTimeframe{
+				StartTime: metav1.NewTime(now.Add(-defaults.Timeframe.Duration)),
+				EndTime:   metav1.NewTime(now),
+			}
+			timeframeSet = true
+		}
+		if defaults.RequiresApproval {
+			supportArchive.Spec.RequiresApproval = true
+		}
+		if supportArchive.Spec.Notifications == nil && defaults.Notifications != nil {
+			supportArchive.Spec.Notifications = defaults.Notifications.DeepCopy()
+		}
+	}
+}
+
+// InProgress returns true if the supportArchive is neither created, rejected, cancelled nor deleted.
+func InProgress(supportArchive *v1.SupportArchive) bool {
+	if supportArchive.DeletionTimestamp != nil || supportArchive.Spec.Cancel {
+		return false
+	}
+	if approval := supportArchive.Status.Approval; approval != nil && approval.Decision == v1.ApprovalDecisionRejected {
+		return false
+	}
+
+	return !meta.IsStatusConditionTrue(supportArchive.Status.Conditions, v1.ConditionSupportArchiveCreated)
+}
+
+func countInProgress(supportArchive *v1.SupportArchive, namespaceArchives []v1.SupportArchive) int {
+	count := 0
+	for _, other := range namespaceArchives {
+		if other.Namespace != supportArchive.Namespace || other.Name == supportArchive.Name {
+			continue
+		}
+		if InProgress(&other) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// excludes returns whether the category is excluded and whether the category is known at all.
+func excludes(excluded v1.ExcludedContents, category v1.ContentCategory) (bool, bool) {
+	switch category {
+	case v1.ContentSystemState:
+		return excluded.SystemState, true
+	case v1.ContentSensitiveData:
+		return excluded.SensitiveData, true
+	case v1.ContentEvents:
+		return excluded.Events, true
+	case v1.ContentLogs:
+		return excluded.Logs, true
+	case v1.ContentVolumeInfo:
+		return excluded.VolumeInfo, true
+	case v1.ContentSystemInfo:
+		return excluded.SystemInfo, true
+	default:
+		return false, false
+	}
+}
+
+func sortedByName(policies []v1.SupportArchivePolicy) []v1.SupportArchivePolicy {
+	return slices.SortedFunc(slices.Values(policies), func(a, b v1.SupportArchivePolicy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+}
@@ -0,0 +1,186 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var testNow = time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC)
+
+func newArchive(name string, timeframe time.Duration) *v1.SupportArchive {
+	return &v1.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ecosystem"},
+		Spec: v1.SupportArchiveSpec{
+			ContentTimeframe: v1.ContentTimeframe{
+				StartTime: metav1.NewTime(testNow.Add(-timeframe)),
+				EndTime:   metav1.NewTime(testNow),
+			},
+		},
+	}
+}
+
+func newCreatedArchive(name string) v1.SupportArchive {
+	archive := newArchive(name, time.Hour)
+	archive.Status.Conditions = []metav1.Condition{{Type: v1.ConditionSupportArchiveCreated, Status: metav1.ConditionTrue}}
+	return *archive
+}
+
+func TestEvaluate(t *testing.T) {
+	policies := []v1.SupportArchivePolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "timeframe"},
+			Spec: v1.SupportArchivePolicySpec{Limits: &v1.PolicyLimits{
+				MaxTimeframe:              &metav1.Duration{Duration: 7 * 24 * time.Hour},
+				MaxConcurrentPerNamespace: ptr.To(int32(2)),
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-secrets"},
+			Spec:       v1.SupportArchivePolicySpec{ForbiddenContents: []v1.ContentCategory{v1.ContentSensitiveData}},
+		},
+	}
+
+	t.Run("should accept compliant supportArchive", func(t *testing.T) {
+		// given
+		archive := newArchive("my-archive", 24*time.Hour)
+		archive.Spec.ExcludedContents.SensitiveData = true
+		others := []v1.SupportArchive{*newArchive("in-progress", time.Hour), newCreatedArchive("created"), *archive}
+
+		// when
+		err := Evaluate(policies, archive, others)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should join all violations", func(t *testing.T) {
+		// given
+		archive := newArchive("my-archive", 30*24*time.Hour)
+		others := []v1.SupportArchive{*newArchive("first", time.Hour), *newArchive("second", time.Hour)}
+
+		// when
+		err := Evaluate(policies, archive, others)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrViolation)
+		assert.Equal(t, "supportArchivePolicy no-secrets: content SensitiveData must be excluded\n"+
+			"supportArchivePolicy timeframe: content timeframe of 720h0m0s exceeds the maximum of 168h0m0s\n"+
+			"supportArchivePolicy timeframe: namespace ecosystem already has 2 supportArchives in progress, the maximum is 2", err.Error())
+		var violation *Violation
+		require.True(t, errors.As(err, &violation))
+		assert.Equal(t, "no-secrets", violation.Policy)
+	})
+	t.Run("should not count supportArchives of other namespaces, rejected or deleted ones", func(t *testing.T) {
+		// given
+		archive := newArchive("my-archive", time.Hour)
+		archive.Spec.ExcludedContents.SensitiveData = true
+		otherNamespace := newArchive("other-namespace", time.Hour)
+		otherNamespace.Namespace = "other"
+		rejected := newArchive("rejected", time.Hour)
+		rejected.Status.Approval = &v1.ApprovalStatus{Decision: v1.ApprovalDecisionRejected}
+		deleted := newArchive("deleted", time.Hour)
+		deleted.DeletionTimestamp = &metav1.Time{Time: testNow}
+		others := []v1.SupportArchive{*otherNamespace, *rejected, *deleted, *newArchive("in-progress", time.Hour)}
+
+		// when
+		err := Evaluate(policies, archive, others)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should report unknown content categories", func(t *testing.T) {
+		// given
+		unknown := []v1.SupportArchivePolicy{{
+			ObjectMeta: metav1.ObjectMeta{Name: "future"},
+			Spec:       v1.SupportArchivePolicySpec{ForbiddenContents: []v1.ContentCategory{"Metrics"}},
+		}}
+		archive := newArchive("my-archive", time.Hour)
+		archive.Spec.ExcludedContents = v1.ExcludedContents{SystemState: true, SensitiveData: true, Events: true, Logs: true, VolumeInfo: true, SystemInfo: true}
+
+		// when
+		err := Evaluate(unknown, archive, nil)
+
+		// then
+		require.ErrorIs(t, err, ErrViolation)
+		assert.EqualError(t, err, "supportArchivePolicy future: unknown content category Metrics")
+	})
+	t.Run("should accept everything without policies", func(t *testing.T) {
+		// when
+		err := Evaluate(nil, newArchive("my-archive", 365*24*time.Hour), nil)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestApplyDefaults(t *testing.T) {
+	notifications := &v1.Notifications{Webhooks: []v1.WebhookNotification{{Name: "chat", URL: "https://chat.example.com"}}}
+	policies := []v1.SupportArchivePolicy{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "b-defaults"},
+			Spec: v1.SupportArchivePolicySpec{Defaults: &v1.PolicyDefaults{
+				Timeframe:     &metav1.Duration{Duration: 24 * time.Hour},
+				Notifications: &v1.Notifications{},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "a-defaults"},
+			Spec: v1.SupportArchivePolicySpec{Defaults: &v1.PolicyDefaults{
+				Timeframe:        &metav1.Duration{Duration: time.Hour},
+				RequiresApproval: true,
+				Notifications:    notifications,
+			}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "limits-only"}},
+	}
+
+	t.Run("should apply defaults of alphabetically first policy", func(t *testing.T) {
+		// given
+		archive := &v1.SupportArchive{}
+
+		// when
+		ApplyDefaults(policies, archive, testNow)
+
+		// then
+		assert.Equal(t, testNow.Add(-time.Hour), archive.Spec.ContentTimeframe.StartTime.Time)
+		assert.Equal(t, testNow, archive.Spec.ContentTimeframe.EndTime.Time)
+		assert.True(t, archive.Spec.RequiresApproval)
+		assert.Equal(t, notifications, archive.Spec.Notifications)
+		assert.NotSame(t, notifications, archive.Spec.Notifications)
+	})
+	t.Run("should keep fields set by the user", func(t *testing.T) {
+		// given
+		archive := newArchive("my-archive", 2*time.Hour)
+		archive.Spec.Notifications = &v1.Notifications{}
+		expected := archive.Spec.ContentTimeframe
+
+		// when
+		ApplyDefaults(policies, archive, testNow.Add(time.Hour))
+
+		// then
+		assert.Equal(t, expected, archive.Spec.ContentTimeframe)
+		assert.Equal(t, &v1.Notifications{}, archive.Spec.Notifications)
+	})
+}
+
+func TestInProgress(t *testing.T) {
+	created := newCreatedArchive("created")
+	approved := newArchive("approved", time.Hour)
+	approved.Status.Approval = &v1.ApprovalStatus{Decision: v1.ApprovalDecisionApproved}
+	cancelled := newArchive("cancelled", time.Hour)
+	cancelled.Spec.Cancel = true
+
+	assert.True(t, InProgress(newArchive("new", time.Hour)))
+	assert.True(t, InProgress(approved))
+	assert.False(t, InProgress(&created))
+	assert.False(t, InProgress(cancelled))
+}
@@ -0,0 +1,125 @@
+// Package rbac checks whether the current user is allowed to work with SupportArchives before doing so.
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/client-go/rest"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+const (
+	// ResourceSupportArchives is the resource name of SupportArchives in RBAC rules.
+	ResourceSupportArchives = "supportarchives"
+	// SubresourceStatus is the status subresource of SupportArchives.
+	SubresourceStatus = "status"
+	// SubresourceApproval is not served by the API server. The approval webhook only accepts approvals and rejections
+	// by users that may perform VerbApprove on it.
+	SubresourceApproval = "approval"
+	// VerbApprove is the verb to approve or reject SupportArchives that require approval.
+	VerbApprove = "approve"
+)
+
+// ErrNotAllowed is returned by Check if the current user is not allowed to perform the action.
+var ErrNotAllowed = errors.New("not allowed")
+
+// Decision is the result of an access review.
+type Decision struct {
+	// Allowed is true if the action is allowed.
+	Allowed bool
+	// Reason explains the decision, if the authorizer gave an explanation.
+	Reason string
+}
+
+// AccessChecker asks the API server whether the current user may perform actions on SupportArchives.
+type AccessChecker interface {
+	// Review returns whether the current user may perform the verb on the subresource of SupportArchives in the
+	// namespace. An empty subresource checks the SupportArchives themselves and an empty namespace all namespaces.
+	Review(ctx context.Context, namespace string, verb string, subresource string) (Decision, error)
+	// Check returns an error wrapping ErrNotAllowed if the current user may not perform the verb, see Review.
+	Check(ctx context.Context, namespace string, verb string, subresource string) error
+}
+
+type accessChecker struct {
+	reviews authorizationv1client.SelfSubjectAccessReviewInterface
+}
+
+// NewAccessChecker creates an AccessChecker for the user of the given config.
+func NewAccessChecker(config *rest.Config) (AccessChecker, error) {
+	authorizationClient, err := authorizationv1client.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authorization client: %w", err)
+	}
+
+	return NewAccessCheckerForClient(authorizationClient), nil
+}
+
+// NewAccessCheckerForClient creates an AccessChecker that uses the given authorization client.
+func NewAccessCheckerForClient(client authorizationv1client.SelfSubjectAccessReviewsGetter) AccessChecker {
+	return &accessChecker{reviews: client.SelfSubjectAccessReviews()}
+}
+
+func (c *accessChecker) Review(ctx context.Context, namespace string, verb string, subresource string) (Decision, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        verb,
+				Group:       v1.GroupVersion.Group,
+				Resource:    ResourceSupportArchives,
+				Subresource: subresource,
+			},
+		},
+	}
+
+	result, err := c.reviews.Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to review access to %s %s: %w", verb, resourceName(subresource), err)
+	}
+
+	reason := result.Status.Reason
+	if reason == "" {
+		reason = result.Status.EvaluationError
+	}
+
+	return Decision{Allowed: result.Status.Allowed, Reason: reason}, nil
+}
+
+func (c *accessChecker) Check(ctx context.Context, namespace string, verb string, subresource string) error {
+	decision, err := c.Review(ctx, namespace, verb, subresource)
+	if err != nil {
+		return err
+	}
+	if decision.Allowed {
+		return nil
+	}
+
+	err = fmt.Errorf("%w to %s %s %s", ErrNotAllowed, verb, resourceName(subresource), scope(namespace))
+	if decision.Reason != "" {
+		err = fmt.Errorf("%w: %s", err, decision.Reason)
+	}
+
+	return err
+}
+
+func resourceName(subresource string) string {
+	if subresource == "" {
+		return ResourceSupportArchives
+	}
+
+	return ResourceSupportArchives + "/" + subresource
+}
+
+func scope(namespace string) string {
+	if namespace == "" {
+		return "in all namespaces"
+	}
+
+	return fmt.Sprintf("in namespace %s", namespace)
+}
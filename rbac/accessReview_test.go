@@ -0,0 +1,116 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/rest"
+)
+
+var testCtx = context.Background()
+
+// newReviewingClient answers access reviews with the given status and records the reviewed attributes.
+func newReviewingClient(status authorizationv1.SubjectAccessReviewStatus, err error) (*fake.Clientset, *[]authorizationv1.ResourceAttributes) {
+	var reviewed []authorizationv1.ResourceAttributes
+	clientSet := fake.NewClientset()
+	clientSet.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		reviewed = append(reviewed, *review.Spec.ResourceAttributes)
+		review.Status = status
+		return true, review, err
+	})
+
+	return clientSet, &reviewed
+}
+
+func TestNewAccessChecker(t *testing.T) {
+	t.Run("should create checker", func(t *testing.T) {
+		// when
+		checker, err := NewAccessChecker(&rest.Config{})
+
+		// then
+		require.NoError(t, err)
+		assert.NotNil(t, checker)
+	})
+}
+
+func Test_accessChecker_Review(t *testing.T) {
+	t.Run("should review access to supportArchives", func(t *testing.T) {
+		// given
+		clientSet, reviewed := newReviewingClient(authorizationv1.SubjectAccessReviewStatus{Allowed: true, Reason: "RBAC: allowed by ClusterRole"}, nil)
+		checker := NewAccessCheckerForClient(clientSet.AuthorizationV1())
+
+		// when
+		decision, err := checker.Review(testCtx, "ecosystem", "patch", SubresourceStatus)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Decision{Allowed: true, Reason: "RBAC: allowed by ClusterRole"}, decision)
+		assert.Equal(t, []authorizationv1.ResourceAttributes{{
+			Namespace:   "ecosystem",
+			Verb:        "patch",
+			Group:       "k8s.cloudogu.com",
+			Resource:    "supportarchives",
+			Subresource: "status",
+		}}, *reviewed)
+	})
+	t.Run("should fail if the review fails", func(t *testing.T) {
+		// given
+		clientSet, _ := newReviewingClient(authorizationv1.SubjectAccessReviewStatus{}, errors.New("connection refused"))
+		checker := NewAccessCheckerForClient(clientSet.AuthorizationV1())
+
+		// when
+		_, err := checker.Review(testCtx, "ecosystem", "create", "")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to review access to create supportarchives: connection refused")
+	})
+}
+
+func Test_accessChecker_Check(t *testing.T) {
+	t.Run("should succeed if allowed", func(t *testing.T) {
+		// given
+		clientSet, _ := newReviewingClient(authorizationv1.SubjectAccessReviewStatus{Allowed: true}, nil)
+		checker := NewAccessCheckerForClient(clientSet.AuthorizationV1())
+
+		// when
+		err := checker.Check(testCtx, "ecosystem", "create", "")
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail with reason if denied", func(t *testing.T) {
+		// given
+		clientSet, _ := newReviewingClient(authorizationv1.SubjectAccessReviewStatus{Allowed: false, Reason: "no matching role"}, nil)
+		checker := NewAccessCheckerForClient(clientSet.AuthorizationV1())
+
+		// when
+		err := checker.Check(testCtx, "ecosystem", "create", "")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrNotAllowed)
+		assert.EqualError(t, err, "not allowed to create supportarchives in namespace ecosystem: no matching role")
+	})
+	t.Run("should fail with evaluation error in all namespaces", func(t *testing.T) {
+		// given
+		clientSet, _ := newReviewingClient(authorizationv1.SubjectAccessReviewStatus{EvaluationError: "webhook unavailable"}, nil)
+		checker := NewAccessCheckerForClient(clientSet.AuthorizationV1())
+
+		// when
+		err := checker.Check(testCtx, "", "list", "")
+
+		// then
+		require.ErrorIs(t, err, ErrNotAllowed)
+		assert.EqualError(t, err, "not allowed to list supportarchives in all namespaces: webhook unavailable")
+	})
+}
@@ -0,0 +1,131 @@
+package redaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// ReportFileName is the name of the redaction report in the root directory of a support archive.
+const ReportFileName = "redaction-report.json"
+
+// ReportVersion is the version of the report format written by this library.
+const ReportVersion = "v1"
+
+// Entry records how often a redaction rule censored values in a single file of the archive.
+// It deliberately contains no original values.
+type Entry struct {
+	// RuleID identifies the redaction rule that was applied.
+	RuleID string `json:"ruleId"`
+	// Category is the content category of the censored values.
+	Category v1.ContentCategory `json:"category"`
+	// File is the path of the censored file relative to the archive root.
+	File string `json:"file"`
+	// Count is the number of values the rule censored in the file.
+	Count int64 `json:"count"`
+}
+
+// Report is the machine-readable record of all redactions in a support archive.
+type Report struct {
+	// Version is the version of the report format.
+	Version string `json:"version"`
+	// Entries contains one entry per rule and file.
+	Entries []Entry `json:"entries"`
+}
+
+// Summary aggregates the entries of the report into counts per content category as used in the SupportArchiveStatus.
+func (r Report) Summary() []v1.RedactionSummary {
+	counts := map[v1.ContentCategory]int64{}
+	for _, entry := range r.Entries {
+		counts[entry.Category] += entry.Count
+	}
+
+	summary := make([]v1.RedactionSummary, 0, len(counts))
+	for category, count := range counts {
+		summary = append(summary, v1.RedactionSummary{Category: category, Count: count})
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].Category < summary[j].Category
+	})
+
+	return summary
+}
+
+// Write encodes the report as JSON to the given writer.
+func (r Report) Write(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(r)
+	if err != nil {
+		return fmt.Errorf("failed to write redaction report: %w", err)
+	}
+
+	return nil
+}
+
+// ReadReport decodes a redaction report from the given reader.
+func ReadReport(reader io.Reader) (Report, error) {
+	report := Report{}
+	err := json.NewDecoder(reader).Decode(&report)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to read redaction report: %w", err)
+	}
+
+	return report, nil
+}
+
+type entryKey struct {
+	ruleID   string
+	category v1.ContentCategory
+	file     string
+}
+
+// Recorder collects redactions while a support archive is created.
+// It is safe for concurrent use by multiple collectors.
+type Recorder struct {
+	mutex  sync.Mutex
+	counts map[entryKey]int64
+}
+
+// NewRecorder creates a new empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{counts: map[entryKey]int64{}}
+}
+
+// Record adds count redactions of the given rule in the given file.
+// Calls with the same rule, category and file are summed up.
+func (r *Recorder) Record(ruleID string, category v1.ContentCategory, file string, count int64) {
+	if count <= 0 {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.counts[entryKey{ruleID: ruleID, category: category, file: file}] += count
+}
+
+// Report returns the report of all redactions recorded so far, sorted by file and rule.
+func (r *Recorder) Report() Report {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entries := make([]Entry, 0, len(r.counts))
+	for key, count := range r.counts {
+		entries = append(entries, Entry{RuleID: key.ruleID, Category: key.category, File: key.file, Count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].File != entries[j].File {
+			return entries[i].File < entries[j].File
+		}
+		if entries[i].RuleID != entries[j].RuleID {
+			return entries[i].RuleID < entries[j].RuleID
+		}
+		return entries[i].Category < entries[j].Category
+	})
+
+	return Report{Version: ReportVersion, Entries: entries}
+}
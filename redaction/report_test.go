@@ -0,0 +1,107 @@
+package redaction
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+func TestRecorder_Report(t *testing.T) {
+	t.Run("should sum up and sort entries", func(t *testing.T) {
+		// given
+		recorder := NewRecorder()
+		recorder.Record("secret-data", v1.ContentSensitiveData, "secrets/b.yaml", 2)
+		recorder.Record("password-in-log", v1.ContentLogs, "logs/a.log", 1)
+		recorder.Record("secret-data", v1.ContentSensitiveData, "secrets/b.yaml", 3)
+		recorder.Record("ignored", v1.ContentLogs, "logs/a.log", 0)
+
+		// when
+		report := recorder.Report()
+
+		// then
+		assert.Equal(t, ReportVersion, report.Version)
+		assert.Equal(t, []Entry{
+			{RuleID: "password-in-log", Category: v1.ContentLogs, File: "logs/a.log", Count: 1},
+			{RuleID: "secret-data", Category: v1.ContentSensitiveData, File: "secrets/b.yaml", Count: 5},
+		}, report.Entries)
+	})
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		// given
+		recorder := NewRecorder()
+		wg := sync.WaitGroup{}
+
+		// when
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				recorder.Record("rule", v1.ContentLogs, "logs/a.log", 1)
+			}()
+		}
+		wg.Wait()
+
+		// then
+		require.Len(t, recorder.Report().Entries, 1)
+		assert.Equal(t, int64(10), recorder.Report().Entries[0].Count)
+	})
+	t.Run("should return empty entries if nothing was recorded", func(t *testing.T) {
+		// when
+		report := NewRecorder().Report()
+
+		// then
+		assert.NotNil(t, report.Entries)
+		assert.Empty(t, report.Entries)
+	})
+}
+
+func TestReport_Summary(t *testing.T) {
+	// given
+	report := Report{Entries: []Entry{
+		{RuleID: "a", Category: v1.ContentSensitiveData, File: "secrets/a.yaml", Count: 2},
+		{RuleID: "b", Category: v1.ContentLogs, File: "logs/a.log", Count: 1},
+		{RuleID: "a", Category: v1.ContentSensitiveData, File: "secrets/b.yaml", Count: 4},
+	}}
+
+	// when
+	summary := report.Summary()
+
+	// then
+	assert.Equal(t, []v1.RedactionSummary{
+		{Category: v1.ContentLogs, Count: 1},
+		{Category: v1.ContentSensitiveData, Count: 6},
+	}, summary)
+}
+
+func TestReport_Write(t *testing.T) {
+	t.Run("should write and read report", func(t *testing.T) {
+		// given
+		report := Report{Version: ReportVersion, Entries: []Entry{
+			{RuleID: "a", Category: v1.ContentSensitiveData, File: "secrets/a.yaml", Count: 2},
+		}}
+		buffer := &bytes.Buffer{}
+
+		// when
+		err := report.Write(buffer)
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, buffer.String(), `"ruleId": "a"`)
+		readReport, err := ReadReport(buffer)
+		require.NoError(t, err)
+		assert.Equal(t, report, readReport)
+	})
+	t.Run("should fail to read invalid report", func(t *testing.T) {
+		// when
+		_, err := ReadReport(strings.NewReader("{"))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to read redaction report")
+	})
+}
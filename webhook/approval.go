@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+	clientv1 "github.com/cloudogu/k8s-support-archive-lib/client/v1"
+	"github.com/cloudogu/k8s-support-archive-lib/rbac"
+)
+
+// ApprovalPath is the path the approval webhook is served on. It matches approval.webhook.path in the CRD chart.
+const ApprovalPath = "/validate-approval"
+
+// NewApprovalWebhook creates a validating webhook for the approval of SupportArchives. It only accepts approval
+// annotations that are set once by the authenticated user they name, together with no other change. The user must be
+// allowed to approve SupportArchives, see rbac.VerbApprove, which is checked with SubjectAccessReviews. Like the
+// client, it refuses approvals by the requester, which the requester webhook must have set. On the status subresource,
+// it only accepts an approval that matches the approval annotations.
+// Register it with the webhook server of the operator:
+//
+//	mgr.GetWebhookServer().Register(webhook.ApprovalPath, webhook.NewApprovalWebhook(clientset.AuthorizationV1()))
+func NewApprovalWebhook(client authorizationv1client.SubjectAccessReviewsGetter) *admission.Webhook {
+	validator := &approvalValidator{reviews: client.SubjectAccessReviews()}
+	return &admission.Webhook{Handler: admission.HandlerFunc(validator.validate)}
+}
+
+type approvalValidator struct {
+	reviews authorizationv1client.SubjectAccessReviewInterface
+}
+
+func (v *approvalValidator) validate(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	supportArchive := &v1.SupportArchive{}
+	if err := json.Unmarshal(req.Object.Raw, supportArchive); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode supportArchive: %w", err))
+	}
+	if req.Operation == admissionv1.Create {
+		if v1.HasApprovalAnnotations(supportArchive.Annotations) || supportArchive.Status.Approval != nil {
+			return admission.Denied("a supportArchive cannot be approved or rejected on creation")
+		}
+		return admission.Allowed("")
+	}
+
+	old := &v1.SupportArchive{}
+	if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode old supportArchive: %w", err))
+	}
+	if req.SubResource == "status" {
+		return validateApprovalStatus(old, supportArchive)
+	}
+
+	return v.validateAnnotations(ctx, req, old, supportArchive)
+}
+
+func (v *approvalValidator) validateAnnotations(ctx context.Context, req admission.Request, old, supportArchive *v1.SupportArchive) admission.Response {
+	oldAnnotations := approvalAnnotationsOf(old)
+	annotations := approvalAnnotationsOf(supportArchive)
+	if maps.Equal(oldAnnotations, annotations) {
+		return admission.Allowed("")
+	}
+	if len(oldAnnotations) > 0 {
+		return admission.Denied("the approval annotations are immutable")
+	}
+
+	username := req.UserInfo.Username
+	approval, err := v1.ApprovalFromAnnotations(annotations)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	if approval.DecidedBy != username {
+		return admission.Denied(fmt.Sprintf("annotation %s must be the current user %s", v1.AnnotationApprovalDecidedBy, username))
+	}
+	if !equality.Semantic.DeepEqual(old.Spec, supportArchive.Spec) {
+		return admission.Denied("the spec cannot be changed together with the approval")
+	}
+	if err = clientv1.CheckDecision(old, username, approval.Decision); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	return v.authorize(ctx, req)
+}
+
+// authorize allows the request if its user may approve SupportArchives in the namespace.
+func (v *approvalValidator) authorize(ctx context.Context, req admission.Request) admission.Response {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range req.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &authorizationv1.ResourceAttributes{
+			Namespace:   req.Namespace,
+			Verb:        rbac.VerbApprove,
+			Group:       v1.GroupVersion.Group,
+			Resource:    rbac.ResourceSupportArchives,
+			Subresource: rbac.SubresourceApproval,
+			Name:        req.Name,
+		},
+		User:   req.UserInfo.Username,
+		Groups: req.UserInfo.Groups,
+		UID:    req.UserInfo.UID,
+		Extra:  extra,
+	}}
+
+	result, err := v.reviews.Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to review access to %s %s/%s: %w", rbac.VerbApprove, rbac.ResourceSupportArchives, rbac.SubresourceApproval, err))
+	}
+	if !result.Status.Allowed {
+		return admission.Denied(fmt.Sprintf("user %s is not allowed to %s %s/%s in namespace %s", req.UserInfo.Username, rbac.VerbApprove, rbac.ResourceSupportArchives, rbac.SubresourceApproval, req.Namespace))
+	}
+
+	return admission.Allowed("")
+}
+
+func validateApprovalStatus(old, supportArchive *v1.SupportArchive) admission.Response {
+	if supportArchive.Status.Approval == nil || equality.Semantic.DeepEqual(old.Status.Approval, supportArchive.Status.Approval) {
+		return admission.Allowed("")
+	}
+
+	approval, err := v1.ApprovalFromAnnotations(supportArchive.Annotations)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+	if approval == nil {
+		return admission.Denied("the approval in the status must match the approval annotations")
+	}
+	actual := supportArchive.Status.Approval
+	if actual.Decision != approval.Decision || actual.DecidedBy != approval.DecidedBy || actual.Reason != approval.Reason ||
+		!actual.DecidedAt.Equal(&approval.DecidedAt) {
+		return admission.Denied("the approval in the status must match the approval annotations")
+	}
+
+	return admission.Allowed("")
+}
+
+func approvalAnnotationsOf(supportArchive *v1.SupportArchive) map[string]string {
+	annotations := map[string]string{}
+	for _, key := range []string{v1.AnnotationApprovalDecision, v1.AnnotationApprovalDecidedBy, v1.AnnotationApprovalDecidedAt, v1.AnnotationApprovalReason} {
+		if value, ok := supportArchive.Annotations[key]; ok {
+			annotations[key] = value
+		}
+	}
+
+	return annotations
+}
@@ -0,0 +1,308 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+var testDecidedAt = metav1.NewTime(time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC))
+
+func archiveRequiringApproval() *v1.SupportArchive {
+	return &v1.SupportArchive{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-archive", Namespace: "ecosystem", Annotations: map[string]string{"note": "incident"}},
+		Spec:       v1.SupportArchiveSpec{Requester: "alice", RequiresApproval: true},
+	}
+}
+
+func withApproval(archive *v1.SupportArchive, decision v1.ApprovalDecision, decidedBy string) *v1.SupportArchive {
+	approved := archive.DeepCopy()
+	for key, value := range v1.ApprovalAnnotations(v1.ApprovalStatus{Decision: decision, DecidedBy: decidedBy, DecidedAt: testDecidedAt, Reason: "checked"}) {
+		approved.Annotations[key] = value
+	}
+
+	return approved
+}
+
+// newReviewingClient answers access reviews with allowed and records the reviews.
+func newReviewingClient(allowed bool, err error) (*fake.Clientset, *[]authorizationv1.SubjectAccessReviewSpec) {
+	var reviewed []authorizationv1.SubjectAccessReviewSpec
+	clientSet := fake.NewClientset()
+	clientSet.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		reviewed = append(reviewed, review.Spec)
+		review.Status.Allowed = allowed
+		return true, review, err
+	})
+
+	return clientSet, &reviewed
+}
+
+func newUpdateRequest(t *testing.T, username string, subResource string, old, object *v1.SupportArchive) admission.Request {
+	oldRaw, err := json.Marshal(old)
+	require.NoError(t, err)
+	raw, err := json.Marshal(object)
+	require.NoError(t, err)
+
+	req := newRequest(admissionv1.Update, username, string(raw))
+	req.OldObject = runtime.RawExtension{Raw: oldRaw}
+	req.SubResource = subResource
+	req.Namespace = object.Namespace
+	req.Name = object.Name
+	return req
+}
+
+func TestNewApprovalWebhook(t *testing.T) {
+	allowingClient, _ := newReviewingClient(true, nil)
+
+	t.Run("should allow approval by another user", func(t *testing.T) {
+		// given
+		clientSet, reviewed := newReviewingClient(true, nil)
+		sut := NewApprovalWebhook(clientSet.AuthorizationV1())
+		old := archiveRequiringApproval()
+		req := newUpdateRequest(t, "bob", "", old, withApproval(old, v1.ApprovalDecisionApproved, "bob"))
+		req.UserInfo = authenticationv1.UserInfo{Username: "bob", UID: "42", Groups: []string{"approvers"}, Extra: map[string]authenticationv1.ExtraValue{"scopes": {"all"}}}
+
+		// when
+		resp := sut.Handle(testCtx, req)
+
+		// then
+		assert.True(t, resp.Allowed)
+		assert.Equal(t, []authorizationv1.SubjectAccessReviewSpec{{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   "ecosystem",
+				Verb:        "approve",
+				Group:       "k8s.cloudogu.com",
+				Resource:    "supportarchives",
+				Subresource: "approval",
+				Name:        "my-archive",
+			},
+			User:   "bob",
+			Groups: []string{"approvers"},
+			UID:    "42",
+			Extra:  map[string]authorizationv1.ExtraValue{"scopes": {"all"}},
+		}}, *reviewed)
+	})
+	t.Run("should deny approval by users that may not approve", func(t *testing.T) {
+		// given
+		clientSet, _ := newReviewingClient(false, nil)
+		sut := NewApprovalWebhook(clientSet.AuthorizationV1())
+		old := archiveRequiringApproval()
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "bob", "", old, withApproval(old, v1.ApprovalDecisionApproved, "bob")))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "user bob is not allowed to approve supportarchives/approval in namespace ecosystem", resp.Result.Message)
+	})
+	t.Run("should fail if access cannot be reviewed", func(t *testing.T) {
+		// given
+		clientSet, _ := newReviewingClient(false, assert.AnError)
+		sut := NewApprovalWebhook(clientSet.AuthorizationV1())
+		old := archiveRequiringApproval()
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "bob", "", old, withApproval(old, v1.ApprovalDecisionApproved, "bob")))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, int32(500), resp.Result.Code)
+		assert.Contains(t, resp.Result.Message, "failed to review access to approve supportarchives/approval")
+	})
+	t.Run("should allow rejection by the requester", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := archiveRequiringApproval()
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "alice", "", old, withApproval(old, v1.ApprovalDecisionRejected, "alice")))
+
+		// then
+		assert.True(t, resp.Allowed)
+	})
+	t.Run("should deny approval by the requester", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := archiveRequiringApproval()
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "alice", "", old, withApproval(old, v1.ApprovalDecisionApproved, "alice")))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "the requester cannot approve their own supportArchive", resp.Result.Message)
+	})
+	t.Run("should deny forged decider", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := archiveRequiringApproval()
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "alice", "", old, withApproval(old, v1.ApprovalDecisionApproved, "bob")))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "annotation k8s.cloudogu.com/approval-decided-by must be the current user alice", resp.Result.Message)
+	})
+	t.Run("should deny approval without requester", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := archiveRequiringApproval()
+		old.Spec.Requester = ""
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "bob", "", old, withApproval(old, v1.ApprovalDecisionApproved, "bob")))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "the requester of the supportArchive is unknown", resp.Result.Message)
+	})
+	t.Run("should deny changed decision", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := withApproval(archiveRequiringApproval(), v1.ApprovalDecisionRejected, "carol")
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "bob", "", old, withApproval(old, v1.ApprovalDecisionApproved, "bob")))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "the approval annotations are immutable", resp.Result.Message)
+	})
+	t.Run("should deny removed decision", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := withApproval(archiveRequiringApproval(), v1.ApprovalDecisionRejected, "carol")
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "carol", "", old, archiveRequiringApproval()))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "the approval annotations are immutable", resp.Result.Message)
+	})
+	t.Run("should deny spec changes together with the approval", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := archiveRequiringApproval()
+		approved := withApproval(old, v1.ApprovalDecisionApproved, "bob")
+		approved.Spec.ExcludedContents.SensitiveData = true
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "bob", "", old, approved))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "the spec cannot be changed together with the approval", resp.Result.Message)
+	})
+	t.Run("should deny incomplete annotations", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := archiveRequiringApproval()
+		approved := old.DeepCopy()
+		approved.Annotations[v1.AnnotationApprovalDecision] = "Approved"
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "bob", "", old, approved))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "annotation k8s.cloudogu.com/approval-decided-by must not be empty", resp.Result.Message)
+	})
+	t.Run("should allow updates without approval changes", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := withApproval(archiveRequiringApproval(), v1.ApprovalDecisionApproved, "bob")
+		cancelled := old.DeepCopy()
+		cancelled.Spec.Cancel = true
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "alice", "", old, cancelled))
+
+		// then
+		assert.True(t, resp.Allowed)
+	})
+	t.Run("should deny approval annotations on create", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		raw, err := json.Marshal(withApproval(archiveRequiringApproval(), v1.ApprovalDecisionApproved, "bob"))
+		require.NoError(t, err)
+
+		// when
+		resp := sut.Handle(testCtx, newRequest(admissionv1.Create, "bob", string(raw)))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "a supportArchive cannot be approved or rejected on creation", resp.Result.Message)
+	})
+	t.Run("should allow status approval matching the annotations", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := withApproval(archiveRequiringApproval(), v1.ApprovalDecisionApproved, "bob")
+		updated := old.DeepCopy()
+		updated.Status.Approval = &v1.ApprovalStatus{Decision: v1.ApprovalDecisionApproved, DecidedBy: "bob", DecidedAt: testDecidedAt, Reason: "checked"}
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "system:serviceaccount:ecosystem:operator", "status", old, updated))
+
+		// then
+		assert.True(t, resp.Allowed)
+	})
+	t.Run("should deny forged status approval", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := withApproval(archiveRequiringApproval(), v1.ApprovalDecisionRejected, "alice")
+		updated := old.DeepCopy()
+		updated.Status.Approval = &v1.ApprovalStatus{Decision: v1.ApprovalDecisionApproved, DecidedBy: "alice", DecidedAt: testDecidedAt, Reason: "checked"}
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "alice", "status", old, updated))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "the approval in the status must match the approval annotations", resp.Result.Message)
+	})
+	t.Run("should deny status approval without annotations", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		old := archiveRequiringApproval()
+		updated := old.DeepCopy()
+		updated.Status.Approval = &v1.ApprovalStatus{Decision: v1.ApprovalDecisionApproved, DecidedBy: "bob", DecidedAt: testDecidedAt}
+
+		// when
+		resp := sut.Handle(testCtx, newUpdateRequest(t, "bob", "status", old, updated))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "the approval in the status must match the approval annotations", resp.Result.Message)
+	})
+	t.Run("should fail for invalid old object", func(t *testing.T) {
+		// given
+		sut := NewApprovalWebhook(allowingClient.AuthorizationV1())
+		req := newRequest(admissionv1.Update, "bob", `{}`)
+		req.OldObject = runtime.RawExtension{Raw: []byte(`{`)}
+
+		// when
+		resp := sut.Handle(testCtx, req)
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, int32(400), resp.Result.Code)
+		assert.Contains(t, resp.Result.Message, "failed to decode old supportArchive")
+	})
+}
@@ -0,0 +1,48 @@
+// Package webhook contains admission webhooks for SupportArchives that are served by the support archive operator.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1 "github.com/cloudogu/k8s-support-archive-lib/api/v1"
+)
+
+// RequesterPath is the path the requester webhook is served on. It matches requesterWebhook.path in the CRD chart.
+const RequesterPath = "/mutate-requester"
+
+const requesterPatchPath = "/spec/requester"
+
+// NewRequesterWebhook creates a mutating webhook that sets the requester of new SupportArchives to the authenticated
+// user that creates them. A requester given by the user is overwritten, so that it can not be spoofed.
+// Register it with the webhook server of the operator:
+//
+//	mgr.GetWebhookServer().Register(webhook.RequesterPath, webhook.NewRequesterWebhook())
+func NewRequesterWebhook() *admission.Webhook {
+	return &admission.Webhook{Handler: admission.HandlerFunc(setRequester)}
+}
+
+func setRequester(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create {
+		return admission.Allowed("")
+	}
+	if req.UserInfo.Username == "" {
+		return admission.Denied("the requester of the supportArchive is unknown")
+	}
+
+	supportArchive := &v1.SupportArchive{}
+	if err := json.Unmarshal(req.Object.Raw, supportArchive); err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode supportArchive: %w", err))
+	}
+	if supportArchive.Spec.Requester == req.UserInfo.Username {
+		return admission.Allowed("")
+	}
+
+	return admission.Patched("", jsonpatch.NewOperation("add", requesterPatchPath, req.UserInfo.Username))
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var testCtx = context.Background()
+
+func newRequest(operation admissionv1.Operation, username string, object string) admission.Request {
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UID:       "1234",
+		Operation: operation,
+		UserInfo:  authenticationv1.UserInfo{Username: username},
+		Object:    runtime.RawExtension{Raw: []byte(object)},
+	}}
+}
+
+func TestNewRequesterWebhook(t *testing.T) {
+	t.Run("should set requester on create", func(t *testing.T) {
+		// given
+		sut := NewRequesterWebhook()
+
+		// when
+		resp := sut.Handle(testCtx, newRequest(admissionv1.Create, "alice", `{"spec":{"excludedContents":{}}}`))
+
+		// then
+		assert.True(t, resp.Allowed)
+		require.Len(t, resp.Patches, 1)
+		assert.Equal(t, jsonpatch.NewOperation("add", "/spec/requester", "alice"), resp.Patches[0])
+		assert.Equal(t, admissionv1.PatchTypeJSONPatch, *resp.PatchType)
+	})
+	t.Run("should overwrite spoofed requester", func(t *testing.T) {
+		// given
+		sut := NewRequesterWebhook()
+
+		// when
+		resp := sut.Handle(testCtx, newRequest(admissionv1.Create, "alice", `{"spec":{"requester":"bob"}}`))
+
+		// then
+		assert.True(t, resp.Allowed)
+		require.Len(t, resp.Patches, 1)
+		assert.Equal(t, "alice", resp.Patches[0].Value)
+	})
+	t.Run("should not patch if requester is already the user", func(t *testing.T) {
+		// given
+		sut := NewRequesterWebhook()
+
+		// when
+		resp := sut.Handle(testCtx, newRequest(admissionv1.Create, "alice", `{"spec":{"requester":"alice"}}`))
+
+		// then
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Patches)
+	})
+	t.Run("should allow updates unchanged", func(t *testing.T) {
+		// given
+		sut := NewRequesterWebhook()
+
+		// when
+		resp := sut.Handle(testCtx, newRequest(admissionv1.Update, "bob", `{"spec":{"requester":"alice"}}`))
+
+		// then
+		assert.True(t, resp.Allowed)
+		assert.Empty(t, resp.Patches)
+	})
+	t.Run("should deny create without user", func(t *testing.T) {
+		// given
+		sut := NewRequesterWebhook()
+
+		// when
+		resp := sut.Handle(testCtx, newRequest(admissionv1.Create, "", `{"spec":{}}`))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, "the requester of the supportArchive is unknown", resp.Result.Message)
+	})
+	t.Run("should fail for invalid object", func(t *testing.T) {
+		// given
+		sut := NewRequesterWebhook()
+
+		// when
+		resp := sut.Handle(testCtx, newRequest(admissionv1.Create, "alice", `{`))
+
+		// then
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, int32(400), resp.Result.Code)
+		assert.Contains(t, resp.Result.Message, "failed to decode supportArchive")
+	})
+}